@@ -0,0 +1,329 @@
+package etherkit
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+//############ ABI Encoding ############
+
+// EncodePacked 按 Solidity abi.encodePacked 的规则编码：
+// 基本类型使用最少字节（uint48 为 6 字节，address 为 20 字节，bool 为 1 字节），string、bytes 不带长度，
+// 数组的元素补齐到 32 字节且不带长度。与 Solidity 一致，不支持 tuple、嵌套数组以及 string/bytes 数组。
+//
+// 值可以是 abi 包对应的 Go 类型，也可以是更宽松的形式：整数可以是任意整数类型、*big.Int 或十进制/0x 字符串，
+// 地址可以是十六进制字符串，bytes/bytesN 可以是 0x 字符串
+func EncodePacked(types []string, values []interface{}) ([]byte, error) {
+	args, converted, err := prepareABIValues(types, values)
+	if err != nil {
+		return nil, err
+	}
+
+	var packed []byte
+	for i, arg := range args {
+		encoded, err := encodePackedValue(arg.Type, converted[i], false)
+		if err != nil {
+			return nil, errors.Wrapf(err, "argument %d (%s)", i, types[i])
+		}
+		packed = append(packed, encoded...)
+	}
+	return packed, nil
+}
+
+// SolidityKeccak256 计算 keccak256(abi.encodePacked(values))，常用于链下订单和 merkle 叶子的哈希
+func SolidityKeccak256(types []string, values []interface{}) (common.Hash, error) {
+	packed, err := EncodePacked(types, values)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(packed), nil
+}
+
+// AbiEncode 按 Solidity abi.encode 的规则编码参数列表（不带方法选择器），值的形式同 EncodePacked，
+// tuple 的值可以是结构体或按组件顺序排列的 []interface{}
+func AbiEncode(types []string, values []interface{}) ([]byte, error) {
+	args, converted, err := prepareABIValues(types, values)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := args.Pack(converted...)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidABIValue, err.Error())
+	}
+	return encoded, nil
+}
+
+// AbiDecode 按 Solidity abi.decode 的规则解码参数列表
+func AbiDecode(types []string, data []byte) ([]interface{}, error) {
+	args, err := parseABITypes(types)
+	if err != nil {
+		return nil, err
+	}
+	values, err := args.UnpackValues(data)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidABIValue, err.Error())
+	}
+	return values, nil
+}
+
+// parseABITypes 解析类型列表，类型写法同人类可读 ABI，如 uint、address[]、(address,uint256)、tuple(address to, uint amount)
+func parseABITypes(types []string) (abi.Arguments, error) {
+	args := make(abi.Arguments, len(types))
+	for i, t := range types {
+		marshaling, err := parseHumanArgument(strings.TrimSpace(t))
+		if err == nil && marshaling.Indexed {
+			err = errors.New("unexpected indexed")
+		}
+		var typ abi.Type
+		if err == nil {
+			typ, err = abi.NewType(marshaling.Type, "", marshaling.Components)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidABI, "type %q: %v", t, err)
+		}
+		args[i] = abi.Argument{Type: typ}
+	}
+	return args, nil
+}
+
+func prepareABIValues(types []string, values []interface{}) (abi.Arguments, []interface{}, error) {
+	if len(types) != len(values) {
+		return nil, nil, errors.Wrapf(ErrInvalidABIValue, "%d types but %d values", len(types), len(values))
+	}
+	args, err := parseABITypes(types)
+	if err != nil {
+		return nil, nil, err
+	}
+	converted := make([]interface{}, len(values))
+	for i, arg := range args {
+		if converted[i], err = convertABIValue(arg.Type, values[i]); err != nil {
+			return nil, nil, errors.Wrapf(err, "argument %d (%s)", i, types[i])
+		}
+	}
+	return args, converted, nil
+}
+
+// convertABIValue 将宽松形式的值转换为 abi 包要求的 Go 类型
+func convertABIValue(typ abi.Type, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, errors.Wrapf(ErrInvalidABIValue, "nil value for %s", typ.String())
+	}
+	target := typ.GetType()
+	v := reflect.ValueOf(value)
+	// 整数和数组逐个元素转换，以便对已是目标类型的值（如 []*big.Int）也做范围检查
+	if v.Type() == target && typ.T != abi.IntTy && typ.T != abi.UintTy && typ.T != abi.SliceTy && typ.T != abi.ArrayTy {
+		return value, nil
+	}
+
+	switch typ.T {
+	case abi.IntTy, abi.UintTy:
+		n, err := toABIBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+		if !fitsABIInteger(n, typ) {
+			return nil, errors.Wrapf(ErrInvalidABIValue, "%s out of range for %s", n, typ.String())
+		}
+		if target.Kind() == reflect.Ptr {
+			return n, nil
+		}
+		out := reflect.New(target).Elem()
+		if typ.T == abi.IntTy {
+			out.SetInt(n.Int64())
+		} else {
+			out.SetUint(n.Uint64())
+		}
+		return out.Interface(), nil
+	case abi.AddressTy:
+		// 与 ethers 一致，大小写混合的地址字符串必须符合 EIP-55 校验和
+		address, err := parseAddress(value, nil)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidABIValue, err.Error())
+		}
+		return address, nil
+	case abi.BoolTy:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case abi.StringTy:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case abi.BytesTy:
+		if b, err := toABIBytes(value); err == nil {
+			return b, nil
+		}
+	case abi.FixedBytesTy:
+		b, err := toABIBytes(value)
+		if err != nil || len(b) != typ.Size {
+			return nil, errors.Wrapf(ErrInvalidABIValue, "expected %d bytes for %s", typ.Size, typ.String())
+		}
+		out := reflect.New(target).Elem()
+		reflect.Copy(out, reflect.ValueOf(b))
+		return out.Interface(), nil
+	case abi.SliceTy, abi.ArrayTy:
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			break
+		}
+		if typ.T == abi.ArrayTy && v.Len() != typ.Size {
+			return nil, errors.Wrapf(ErrInvalidABIValue, "expected %d elements for %s, got %d", typ.Size, typ.String(), v.Len())
+		}
+		out := reflect.New(target).Elem()
+		if typ.T == abi.SliceTy {
+			out = reflect.MakeSlice(target, v.Len(), v.Len())
+		}
+		for i := 0; i < v.Len(); i++ {
+			elem, err := convertABIValue(*typ.Elem, v.Index(i).Interface())
+			if err != nil {
+				return nil, errors.Wrapf(err, "element %d", i)
+			}
+			out.Index(i).Set(reflect.ValueOf(elem))
+		}
+		return out.Interface(), nil
+	case abi.TupleTy:
+		if v.Kind() == reflect.Struct {
+			return value, nil
+		}
+		items, ok := value.([]interface{})
+		if !ok || len(items) != len(typ.TupleElems) {
+			break
+		}
+		out := reflect.New(target).Elem()
+		for i, elem := range typ.TupleElems {
+			field, err := convertABIValue(*elem, items[i])
+			if err != nil {
+				return nil, errors.Wrapf(err, "component %s", typ.TupleRawNames[i])
+			}
+			out.Field(i).Set(reflect.ValueOf(field))
+		}
+		return out.Interface(), nil
+	}
+	return nil, errors.Wrapf(ErrInvalidABIValue, "cannot use %T as %s", value, typ.String())
+}
+
+// encodePackedValue 按 abi.encodePacked 编码单个值，inArray 为 true 时元素补齐到 32 字节
+func encodePackedValue(typ abi.Type, value interface{}, inArray bool) ([]byte, error) {
+	if inArray {
+		switch typ.T {
+		case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+			return nil, errors.Wrapf(ErrInvalidABIValue, "%s is not supported in packed arrays", typ.String())
+		}
+		encoded, err := abi.Arguments{{Type: typ}}.Pack(value)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidABIValue, err.Error())
+		}
+		return encoded, nil
+	}
+
+	switch typ.T {
+	case abi.IntTy, abi.UintTy:
+		n, err := toABIBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+		if n.Sign() < 0 {
+			n = new(big.Int).Add(n, new(big.Int).Lsh(big.NewInt(1), uint(typ.Size)))
+		}
+		return n.FillBytes(make([]byte, typ.Size/8)), nil
+	case abi.AddressTy:
+		address := value.(common.Address)
+		return address.Bytes(), nil
+	case abi.BoolTy:
+		if value.(bool) {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	case abi.StringTy:
+		return []byte(value.(string)), nil
+	case abi.BytesTy:
+		return value.([]byte), nil
+	case abi.FixedBytesTy:
+		v := reflect.ValueOf(value)
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		return b, nil
+	case abi.SliceTy, abi.ArrayTy:
+		v := reflect.ValueOf(value)
+		var packed []byte
+		for i := 0; i < v.Len(); i++ {
+			encoded, err := encodePackedValue(*typ.Elem, v.Index(i).Interface(), true)
+			if err != nil {
+				return nil, err
+			}
+			packed = append(packed, encoded...)
+		}
+		return packed, nil
+	}
+	return nil, errors.Wrapf(ErrInvalidABIValue, "%s is not supported by encodePacked", typ.String())
+}
+
+// toABIBigInt 将整数类型、*big.Int 或十进制/0x 十六进制字符串转换为 *big.Int，
+// 不接受 0b/0o 前缀和下划线分隔
+func toABIBigInt(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		if v != nil {
+			return new(big.Int).Set(v), nil
+		}
+	case big.Int:
+		return new(big.Int).Set(&v), nil
+	case string:
+		digits, base := v, 10
+		if strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "0X") {
+			digits, base = v[2:], 16
+		}
+		// 指定进制时 SetString 不接受下划线，但仍会接受符号：十进制只允许负号，十六进制不允许符号
+		if strings.HasPrefix(digits, "+") || (base == 16 && strings.HasPrefix(digits, "-")) {
+			break
+		}
+		if n, ok := new(big.Int).SetString(digits, base); ok {
+			return n, nil
+		}
+	default:
+		rv := reflect.ValueOf(value)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return big.NewInt(rv.Int()), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return new(big.Int).SetUint64(rv.Uint()), nil
+		}
+	}
+	return nil, errors.Wrapf(ErrInvalidABIValue, "cannot use %T as integer", value)
+}
+
+// toABIBytes 将 []byte、字节数组或 0x 字符串转换为 []byte
+func toABIBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		b, err := hexutil.Decode(v)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidABIValue, "%q is not 0x-prefixed hex", v)
+		}
+		return b, nil
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return b, nil
+	}
+	return nil, errors.Wrapf(ErrInvalidABIValue, "cannot use %T as bytes", value)
+}
+
+// fitsABIInteger 整数是否在类型的取值范围内
+func fitsABIInteger(n *big.Int, typ abi.Type) bool {
+	if typ.T == abi.UintTy {
+		return n.Sign() >= 0 && n.BitLen() <= typ.Size
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(typ.Size-1))
+	return n.Cmp(limit) < 0 && n.Cmp(new(big.Int).Neg(limit)) >= 0
+}
@@ -0,0 +1,110 @@
+package etherkit
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestEncodePacked(t *testing.T) {
+	tests := []struct {
+		name     string
+		types    []string
+		values   []interface{}
+		expected string
+	}{
+		{"Negative int and uint48", []string{"int16", "uint48"}, []interface{}{-1, 12}, "0xffff00000000000c"},
+		{"String and uint8", []string{"string", "uint8"}, []interface{}{"Hello", 3}, "0x48656c6c6f03"},
+		{"Address and bool", []string{"address", "bool"}, []interface{}{"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", true}, "0xf39fd6e51aad88f6f4ce6ab8827279cfffb9226601"},
+		{"Bytes and bytes4", []string{"bytes", "bytes4"}, []interface{}{"0x1234", "0xa9059cbb"}, "0x1234a9059cbb"},
+		{"uint256 from string", []string{"uint"}, []interface{}{"0x10"}, "0x0000000000000000000000000000000000000000000000000000000000000010"},
+		{"Array elements padded", []string{"uint8[]", "address[1]"}, []interface{}{[]int{1, 2}, []common.Address{guardTrusted}},
+			"0x" + "0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"00000000000000000000000070997970c51812dc3a010c7d01b50e0d17dc79c8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packed, err := EncodePacked(tt.types, tt.values)
+			if err != nil {
+				t.Fatalf("EncodePacked() failed: %v", err)
+			}
+			if hexutil.Encode(packed) != tt.expected {
+				t.Errorf("EncodePacked() = %s, expected %s", hexutil.Encode(packed), tt.expected)
+			}
+		})
+	}
+
+	hash, err := SolidityKeccak256([]string{"int16", "uint48"}, []interface{}{-1, 12})
+	if err != nil || hash.Hex() != "0x81da7abb5c9c7515f57dab2fc946f01217ab52f3bd8958bc36bd55894451a93c" {
+		t.Errorf("SolidityKeccak256() = %s, %v", hash.Hex(), err)
+	}
+
+	invalid := []struct {
+		types  []string
+		values []interface{}
+	}{
+		{[]string{"uint8"}, []interface{}{256}},
+		{[]string{"int8"}, []interface{}{-129}},
+		{[]string{"uint256"}, []interface{}{-1}},
+		{[]string{"bytes4"}, []interface{}{"0x1234"}},
+		{[]string{"string[]"}, []interface{}{[]string{"a"}}},
+		{[]string{"(address,uint256)"}, []interface{}{[]interface{}{guardTrusted, 1}}},
+		{[]string{"address"}, []interface{}{"0xF39Fd6e51aad88F6F4ce6aB8827279cffFb92266"}},
+		{[]string{"uint256", "bool"}, []interface{}{1}},
+		{[]string{"uint256[]"}, []interface{}{[]*big.Int{big.NewInt(1), big.NewInt(-1)}}},
+		{[]string{"uint8[2]"}, []interface{}{[2]*big.Int{big.NewInt(1), big.NewInt(256)}}},
+		{[]string{"uint256"}, []interface{}{"1_0"}},
+		{[]string{"uint256"}, []interface{}{"0b101"}},
+		{[]string{"uint256"}, []interface{}{"0o17"}},
+		{[]string{"int256"}, []interface{}{"0x-1"}},
+	}
+	for _, tt := range invalid {
+		if _, err := EncodePacked(tt.types, tt.values); !errors.Is(err, ErrInvalidABIValue) {
+			t.Errorf("Expected ErrInvalidABIValue for %v %v, got %v", tt.types, tt.values, err)
+		}
+	}
+	if _, err := EncodePacked([]string{"uint7"}, []interface{}{1}); !errors.Is(err, ErrInvalidABI) {
+		t.Errorf("Expected ErrInvalidABI, got %v", err)
+	}
+}
+
+func TestAbiEncodeDecode(t *testing.T) {
+	types := []string{"address", "uint256", "bytes32", "string", "tuple(address to, uint amount)[]"}
+	salt := common.HexToHash("0x01")
+	values := []interface{}{
+		guardTrusted.Hex(),
+		"1000",
+		salt,
+		"order",
+		[]interface{}{[]interface{}{guardUnrelated, 5}},
+	}
+
+	encoded, err := AbiEncode(types, values)
+	if err != nil {
+		t.Fatalf("AbiEncode() failed: %v", err)
+	}
+	if len(encoded)%32 != 0 || common.BytesToAddress(encoded[:32]) != guardTrusted {
+		t.Errorf("Unexpected encoding: %x", encoded)
+	}
+
+	decoded, err := AbiDecode(types, encoded)
+	if err != nil {
+		t.Fatalf("AbiDecode() failed: %v", err)
+	}
+	if decoded[0] != guardTrusted || decoded[1].(*big.Int).Int64() != 1000 || decoded[2] != [32]byte(salt) || decoded[3] != "order" {
+		t.Errorf("Unexpected decoded values: %v", decoded)
+	}
+	transfers := reflect.ValueOf(decoded[4])
+	if transfers.Len() != 1 || transfers.Index(0).Field(0).Interface() != guardUnrelated || transfers.Index(0).Field(1).Interface().(*big.Int).Int64() != 5 {
+		t.Errorf("Unexpected tuple array: %+v", decoded[4])
+	}
+
+	if _, err := AbiDecode([]string{"uint256", "uint256"}, encoded[:32]); !errors.Is(err, ErrInvalidABIValue) {
+		t.Errorf("Expected ErrInvalidABIValue, got %v", err)
+	}
+}
@@ -3,34 +3,154 @@ package etherkit
 import (
 	"encoding/hex"
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
-	"golang.org/x/crypto/sha3"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
 )
 
 //############ Address ############
 
-// IsValidAddress 验证是否是十六进制地址
+var hexAddressRegexp = regexp.MustCompile("^0x[0-9a-fA-F]{40}$")
+
+// eip1191ChainIDs 采用 EIP-1191 带链ID校验和的网络
+var eip1191ChainIDs = map[int64]bool{
+	RSKMainnetChainID: true,
+	RSKTestnetChainID: true,
+}
+
+// IsValidAddress 验证是否是十六进制地址，不校验大小写。
+// 需要校验 EIP-55 校验和或区分零地址时使用 ValidateAddress
 func IsValidAddress(iAddress interface{}) bool {
-	re := regexp.MustCompile("^0x[0-9a-fA-F]{40}$")
 	switch v := iAddress.(type) {
 	case string:
-		return re.MatchString(v)
+		return hexAddressRegexp.MatchString(v)
 	case common.Address:
-		return re.MatchString(v.Hex())
+		return hexAddressRegexp.MatchString(v.Hex())
 	default:
 		return false
 	}
 }
 
-// PublicKeyBytesToAddress 公钥到地址
-func PublicKeyBytesToAddress(publicKey []byte) common.Address {
-	var buf []byte
+// ValidateAddress 严格校验地址：格式或 EIP-55 校验和错误返回 ErrInvalidAddress，零地址返回 ErrZeroAddress
+func ValidateAddress(iAddress interface{}) error {
+	return validateAddress(iAddress, nil)
+}
+
+// ValidateAddressForChain 与 ValidateAddress 相同，但对采用 EIP-1191 的链（如 RSK）使用带链ID的校验和
+func ValidateAddressForChain(iAddress interface{}, chainID int64) error {
+	if !UsesEIP1191Checksum(chainID) {
+		return validateAddress(iAddress, nil)
+	}
+	return validateAddress(iAddress, &chainID)
+}
+
+// ToChecksumAddress 转换为 EIP-55 校验和格式
+func ToChecksumAddress(iAddress interface{}) (string, error) {
+	address, err := toAddress(iAddress)
+	if err != nil {
+		return "", err
+	}
+	return checksumAddress(address, nil), nil
+}
+
+// ToChecksumAddressForChain 转换为链对应的校验和格式，采用 EIP-1191 的链使用带链ID的校验和，其他链使用 EIP-55
+func ToChecksumAddressForChain(iAddress interface{}, chainID int64) (string, error) {
+	address, err := toAddress(iAddress)
+	if err != nil {
+		return "", err
+	}
+	if !UsesEIP1191Checksum(chainID) {
+		return checksumAddress(address, nil), nil
+	}
+	return checksumAddress(address, &chainID), nil
+}
 
-	hash := sha3.NewLegacyKeccak256()
-	hash.Write(publicKey[1:]) // remove EC prefix 04
-	buf = hash.Sum(nil)
-	address := buf[12:]
+// UsesEIP1191Checksum 链是否采用 EIP-1191 带链ID的校验和
+func UsesEIP1191Checksum(chainID int64) bool {
+	return eip1191ChainIDs[chainID]
+}
+
+func validateAddress(iAddress interface{}, chainID *int64) error {
+	address, err := parseAddress(iAddress, chainID)
+	if err != nil {
+		return err
+	}
+	if address == (common.Address{}) {
+		return ErrZeroAddress
+	}
+	return nil
+}
 
-	return common.HexToAddress(hex.EncodeToString(address))
+// parseAddress 解析地址，字符串必须是0x开头的40位十六进制，大小写混合时校验 EIP-55/EIP-1191 校验和
+func parseAddress(iAddress interface{}, chainID *int64) (common.Address, error) {
+	switch v := iAddress.(type) {
+	case common.Address:
+		return v, nil
+	case *common.Address:
+		if v != nil {
+			return *v, nil
+		}
+	case string:
+		if !hexAddressRegexp.MatchString(v) {
+			break
+		}
+		address := common.HexToAddress(v)
+		body := v[2:]
+		if body != strings.ToLower(body) && body != strings.ToUpper(body) && checksumAddress(address, chainID)[2:] != body {
+			return common.Address{}, errors.Wrapf(ErrInvalidAddress, "%s: checksum mismatch", v)
+		}
+		return address, nil
+	}
+	return common.Address{}, errors.Wrapf(ErrInvalidAddress, "%v", iAddress)
+}
+
+// checksumAddress 计算 EIP-55 校验和地址，chainID 不为 nil 时按 EIP-1191 把 "chainID0x" 前缀计入哈希
+func checksumAddress(address common.Address, chainID *int64) string {
+	if chainID == nil {
+		return address.Hex()
+	}
+
+	lower := hex.EncodeToString(address.Bytes())
+	hash := crypto.Keccak256([]byte(strconv.FormatInt(*chainID, 10) + "0x" + lower))
+	result := []byte(lower)
+	for i, c := range result {
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if c >= 'a' && nibble&0x0f >= 8 {
+			result[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(result)
+}
+
+// PublicKeyBytesToAddress 公钥到地址，支持 ParsePublicKey 接受的所有格式。
+// 公钥格式错误或不在曲线上时返回零地址，需要错误信息时使用 PublicKeyToAddress
+func PublicKeyBytesToAddress(publicKey []byte) common.Address {
+	address, err := PublicKeyToAddress(publicKey)
+	if err != nil {
+		return common.Address{}
+	}
+	return address
+}
+
+// toAddress 将 common.Address、*common.Address 或任意大小写的十六进制字符串转换为地址，不校验 EIP-55 校验和
+func toAddress(iAddress interface{}) (common.Address, error) {
+	switch v := iAddress.(type) {
+	case common.Address:
+		return v, nil
+	case *common.Address:
+		if v != nil {
+			return *v, nil
+		}
+	case string:
+		if common.IsHexAddress(v) {
+			return common.HexToAddress(v), nil
+		}
+	}
+	return common.Address{}, errors.Wrapf(ErrInvalidAddress, "%v", iAddress)
 }
@@ -1,6 +1,8 @@
 package etherkit
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -38,6 +40,95 @@ func TestIsValidAddress(t *testing.T) {
 	}
 }
 
+func TestValidateAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address interface{}
+		target  error
+	}{
+		{"Valid checksum", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", nil},
+		{"Valid lowercase", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", nil},
+		{"Valid common.Address", common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"), nil},
+		{"Bad checksum", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", ErrInvalidAddress},
+		{"No prefix", "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", ErrInvalidAddress},
+		{"Wrong type", 123, ErrInvalidAddress},
+		{"Zero address string", ZeroAddress, ErrZeroAddress},
+		{"Zero common.Address", common.Address{}, ErrZeroAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAddress(tt.address)
+			if tt.target == nil && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.target != nil && !errors.Is(err, tt.target) {
+				t.Errorf("Expected %v, got %v", tt.target, err)
+			}
+		})
+	}
+}
+
+func TestToChecksumAddress(t *testing.T) {
+	// EIP-55 测试向量
+	for _, expected := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		checksum, err := ToChecksumAddress(strings.ToLower(expected))
+		if err != nil {
+			t.Fatalf("ToChecksumAddress() failed: %v", err)
+		}
+		if checksum != expected {
+			t.Errorf("ToChecksumAddress() = %s, expected %s", checksum, expected)
+		}
+		// 非 EIP-1191 链仍使用 EIP-55
+		checksum, err = ToChecksumAddressForChain(expected, MainnetChainID)
+		if err != nil || checksum != expected {
+			t.Errorf("ToChecksumAddressForChain(1) = %s, %v, expected %s", checksum, err, expected)
+		}
+	}
+
+	// EIP-1191 测试向量
+	tests := map[int64][]string{
+		RSKMainnetChainID: {
+			"0x5aaEB6053f3e94c9b9a09f33669435E7ef1bEAeD",
+			"0xFb6916095cA1Df60bb79ce92cE3EA74c37c5d359",
+			"0xDBF03B407c01E7CD3cBea99509D93F8Dddc8C6FB",
+			"0xD1220A0Cf47c7B9BE7a2e6ba89F429762E7B9adB",
+		},
+		RSKTestnetChainID: {
+			"0x5aAeb6053F3e94c9b9A09F33669435E7EF1BEaEd",
+			"0xFb6916095CA1dF60bb79CE92ce3Ea74C37c5D359",
+			"0xdbF03B407C01E7cd3cbEa99509D93f8dDDc8C6fB",
+			"0xd1220a0CF47c7B9Be7A2E6Ba89f429762E7b9adB",
+		},
+	}
+	for chainID, addresses := range tests {
+		for _, expected := range addresses {
+			checksum, err := ToChecksumAddressForChain(strings.ToLower(expected), chainID)
+			if err != nil {
+				t.Fatalf("ToChecksumAddressForChain() failed: %v", err)
+			}
+			if checksum != expected {
+				t.Errorf("ToChecksumAddressForChain(%d) = %s, expected %s", chainID, checksum, expected)
+			}
+			if err := ValidateAddressForChain(expected, chainID); err != nil {
+				t.Errorf("ValidateAddressForChain(%d, %s) failed: %v", chainID, expected, err)
+			}
+			if err := ValidateAddress(expected); !errors.Is(err, ErrInvalidAddress) {
+				t.Errorf("ValidateAddress(%s) expected EIP-55 checksum mismatch, got %v", expected, err)
+			}
+		}
+	}
+
+	if _, err := ToChecksumAddress("0x123"); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("Expected ErrInvalidAddress, got %v", err)
+	}
+}
+
 func TestPublicKeyBytesToAddress(t *testing.T) {
 	// 测试用的公钥 (不包含04前缀)
 	publicKeyBytes := []byte{
@@ -0,0 +1,323 @@
+package etherkit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+//############ Address Book ############
+
+// AddressLabeler 为地址提供可读标签，用于在解码后的交易、日志等输出中替代十六进制地址
+type AddressLabeler interface {
+	Label(address common.Address) (string, bool)
+}
+
+// FormatAddress 格式化地址，有标签时输出 "label (0x...)"，labeler 可以为 nil
+func FormatAddress(address common.Address, labeler AddressLabeler) string {
+	if labeler != nil {
+		if label, ok := labeler.Label(address); ok {
+			return label + " (" + address.Hex() + ")"
+		}
+	}
+	return address.Hex()
+}
+
+// AddressBookEntry 地址簿条目
+type AddressBookEntry struct {
+	Address  common.Address              `json:"address"`
+	Label    string                      `json:"label"`
+	Tags     []string                    `json:"tags,omitempty"`
+	Metadata map[int64]map[string]string `json:"metadata,omitempty"` // 按链ID区分的元数据，如 {1: {"memo": "..."}}
+}
+
+// HasTag 是否包含标签（不区分大小写）
+func (e *AddressBookEntry) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddressBook 地址簿，标签不区分大小写且唯一，并发安全
+type AddressBook struct {
+	mu      sync.RWMutex
+	entries map[common.Address]*AddressBookEntry
+	labels  map[string]common.Address
+}
+
+// NewAddressBook 创建一个空的地址簿
+func NewAddressBook() *AddressBook {
+	return &AddressBook{
+		entries: make(map[common.Address]*AddressBookEntry),
+		labels:  make(map[string]common.Address),
+	}
+}
+
+// Add 添加或更新条目。标签不能为空、不能以0x开头（Resolve 会将其视为地址），
+// 也不能与其他地址的标签重复。标签无效时返回 ErrInvalidLabel
+func (b *AddressBook) Add(entry AddressBookEntry) error {
+	label := strings.TrimSpace(entry.Label)
+	if label == "" || strings.HasPrefix(label, "0x") {
+		return errors.Wrapf(ErrInvalidLabel, "%q", entry.Label)
+	}
+	entry.Label = label
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	key := strings.ToLower(label)
+	if owner, ok := b.labels[key]; ok && owner != entry.Address {
+		return errors.Wrapf(ErrDuplicateLabel, "%s is already used by %s", label, owner.Hex())
+	}
+	if old, ok := b.entries[entry.Address]; ok {
+		delete(b.labels, strings.ToLower(old.Label))
+	}
+	b.entries[entry.Address] = copyAddressBookEntry(&entry)
+	b.labels[key] = entry.Address
+	return nil
+}
+
+// Remove 删除条目
+func (b *AddressBook) Remove(address common.Address) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if entry, ok := b.entries[address]; ok {
+		delete(b.labels, strings.ToLower(entry.Label))
+		delete(b.entries, address)
+	}
+}
+
+// Get 获得地址对应的条目（副本）
+func (b *AddressBook) Get(address common.Address) (AddressBookEntry, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	entry, ok := b.entries[address]
+	if !ok {
+		return AddressBookEntry{}, false
+	}
+	return *copyAddressBookEntry(entry), true
+}
+
+// Label 实现 AddressLabeler
+func (b *AddressBook) Label(address common.Address) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	entry, ok := b.entries[address]
+	if !ok {
+		return "", false
+	}
+	return entry.Label, true
+}
+
+// Resolve 将标签或十六进制地址解析为地址，未知标签返回 ErrAddressNotFound。
+// 大小写混合的地址必须符合 EIP-55 校验和，否则返回 ErrInvalidAddress
+func (b *AddressBook) Resolve(labelOrAddress string) (common.Address, error) {
+	s := strings.TrimSpace(labelOrAddress)
+	if strings.HasPrefix(s, "0x") {
+		return parseAddress(s, nil)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	address, ok := b.labels[strings.ToLower(s)]
+	if !ok {
+		return common.Address{}, errors.Wrap(ErrAddressNotFound, labelOrAddress)
+	}
+	return address, nil
+}
+
+// Entries 按标签排序返回所有条目
+func (b *AddressBook) Entries() []AddressBookEntry {
+	return b.filter(func(*AddressBookEntry) bool { return true })
+}
+
+// EntriesWithTag 按标签排序返回包含指定 tag 的条目
+func (b *AddressBook) EntriesWithTag(tag string) []AddressBookEntry {
+	return b.filter(func(e *AddressBookEntry) bool { return e.HasTag(tag) })
+}
+
+// Len 条目数量
+func (b *AddressBook) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.entries)
+}
+
+func (b *AddressBook) filter(match func(*AddressBookEntry) bool) []AddressBookEntry {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	result := make([]AddressBookEntry, 0, len(b.entries))
+	for _, entry := range b.entries {
+		if match(entry) {
+			result = append(result, *copyAddressBookEntry(entry))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Label) < strings.ToLower(result[j].Label)
+	})
+	return result
+}
+
+//############ Address Book Persistence ############
+
+// addressBookCSVHeader CSV 的列，多个 tag 用 ; 分隔。CSV 不保存按链区分的元数据，需要时使用 JSON
+var addressBookCSVHeader = []string{"address", "label", "tags"}
+
+// addressBookJSONEntry 读取 JSON 时保留原始地址字符串，以便校验 EIP-55 校验和
+type addressBookJSONEntry struct {
+	AddressBookEntry
+	Address string `json:"address"`
+}
+
+// LoadAddressBookJSON 从 JSON 数组读取地址簿，大小写混合的地址必须符合 EIP-55 校验和
+func LoadAddressBookJSON(r io.Reader) (*AddressBook, error) {
+	var entries []addressBookJSONEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, errors.Wrap(err, "failed to decode address book JSON")
+	}
+	book := NewAddressBook()
+	for i, entry := range entries {
+		address, err := parseAddress(entry.Address, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "address book JSON entry %d", i)
+		}
+		entry.AddressBookEntry.Address = address
+		if err := book.Add(entry.AddressBookEntry); err != nil {
+			return nil, err
+		}
+	}
+	return book, nil
+}
+
+// SaveJSON 将地址簿按标签排序写为 JSON 数组
+func (b *AddressBook) SaveJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(b.Entries())
+}
+
+// LoadAddressBookCSV 从带表头的 CSV（address,label,tags）读取地址簿，大小写混合的地址必须符合 EIP-55 校验和
+func LoadAddressBookCSV(r io.Reader) (*AddressBook, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read address book CSV")
+	}
+
+	book := NewAddressBook()
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), addressBookCSVHeader[0]) {
+			continue
+		}
+		if len(record) < 2 {
+			return nil, errors.Errorf("address book CSV line %d: expected at least 2 columns", i+1)
+		}
+		address, err := parseAddress(strings.TrimSpace(record[0]), nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "address book CSV line %d", i+1)
+		}
+		entry := AddressBookEntry{Address: address, Label: record[1]}
+		if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+			for _, tag := range strings.Split(record[2], ";") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					entry.Tags = append(entry.Tags, tag)
+				}
+			}
+		}
+		if err := book.Add(entry); err != nil {
+			return nil, errors.Wrapf(err, "address book CSV line %d", i+1)
+		}
+	}
+	return book, nil
+}
+
+// SaveCSV 将地址簿按标签排序写为 CSV
+func (b *AddressBook) SaveCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(addressBookCSVHeader); err != nil {
+		return err
+	}
+	for _, entry := range b.Entries() {
+		if err := writer.Write([]string{entry.Address.Hex(), entry.Label, strings.Join(entry.Tags, ";")}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// LoadAddressBookFile 根据扩展名（.json 或 .csv）读取地址簿文件
+func LoadAddressBookFile(path string) (*AddressBook, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return LoadAddressBookJSON(f)
+	case ".csv":
+		return LoadAddressBookCSV(f)
+	default:
+		return nil, errors.Errorf("unsupported address book file %s", path)
+	}
+}
+
+// SaveFile 根据扩展名（.json 或 .csv）保存地址簿文件
+func (b *AddressBook) SaveFile(path string) (err error) {
+	var save func(io.Writer) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		save = b.SaveJSON
+	case ".csv":
+		save = b.SaveCSV
+	default:
+		return errors.Errorf("unsupported address book file %s", path)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return save(f)
+}
+
+// AddAddressBook 将地址簿中的所有地址加入可信地址
+func (g *AddressGuard) AddAddressBook(book *AddressBook) {
+	for _, entry := range book.Entries() {
+		g.AddTrusted(entry.Address, entry.Label)
+	}
+}
+
+func copyAddressBookEntry(entry *AddressBookEntry) *AddressBookEntry {
+	c := *entry
+	c.Tags = append([]string(nil), entry.Tags...)
+	if entry.Metadata != nil {
+		c.Metadata = make(map[int64]map[string]string, len(entry.Metadata))
+		for chainID, metadata := range entry.Metadata {
+			m := make(map[string]string, len(metadata))
+			for k, v := range metadata {
+				m[k] = v
+			}
+			c.Metadata[chainID] = m
+		}
+	}
+	return &c
+}
@@ -0,0 +1,212 @@
+package etherkit
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func newTestAddressBook(t *testing.T) *AddressBook {
+	book := NewAddressBook()
+	entries := []AddressBookEntry{
+		{Address: guardTrusted, Label: "Exchange", Tags: []string{"cex", "hot"}, Metadata: map[int64]map[string]string{MainnetChainID: {"memo": "12345"}}},
+		{Address: guardUnrelated, Label: "Treasury", Tags: []string{"internal"}},
+	}
+	for _, entry := range entries {
+		if err := book.Add(entry); err != nil {
+			t.Fatalf("Add() failed: %v", err)
+		}
+	}
+	return book
+}
+
+// badChecksumHex 翻转第一个字母的大小写，得到校验和错误的大小写混合地址
+func badChecksumHex(address common.Address) string {
+	b := []byte(address.Hex())
+	for i := 2; i < len(b); i++ {
+		if b[i] >= 'a' && b[i] <= 'f' {
+			b[i] -= 'a' - 'A'
+			break
+		}
+		if b[i] >= 'A' && b[i] <= 'F' {
+			b[i] += 'a' - 'A'
+			break
+		}
+	}
+	return string(b)
+}
+
+func TestAddressBook(t *testing.T) {
+	book := newTestAddressBook(t)
+
+	address, err := book.Resolve("exchange")
+	if err != nil || address != guardTrusted {
+		t.Errorf("Resolve(exchange) = %s, %v", address.Hex(), err)
+	}
+	address, err = book.Resolve(guardLookAlike.Hex())
+	if err != nil || address != guardLookAlike {
+		t.Errorf("Resolve(hex) = %s, %v", address.Hex(), err)
+	}
+	if _, err := book.Resolve("unknown"); !errors.Is(err, ErrAddressNotFound) {
+		t.Errorf("Expected ErrAddressNotFound, got %v", err)
+	}
+
+	if label, ok := book.Label(guardUnrelated); !ok || label != "Treasury" {
+		t.Errorf("Label() = %s, %v", label, ok)
+	}
+	if s := FormatAddress(guardUnrelated, book); s != "Treasury ("+guardUnrelated.Hex()+")" {
+		t.Errorf("FormatAddress() = %s", s)
+	}
+	if s := FormatAddress(guardLookAlike, book); s != guardLookAlike.Hex() {
+		t.Errorf("FormatAddress() = %s", s)
+	}
+
+	if entries := book.EntriesWithTag("CEX"); len(entries) != 1 || entries[0].Address != guardTrusted {
+		t.Errorf("EntriesWithTag() = %v", entries)
+	}
+
+	// 标签唯一，且不能是地址
+	if err := book.Add(AddressBookEntry{Address: guardLookAlike, Label: "EXCHANGE"}); !errors.Is(err, ErrDuplicateLabel) {
+		t.Errorf("Expected ErrDuplicateLabel, got %v", err)
+	}
+	for _, label := range []string{guardTrusted.Hex(), "0xExchange", "  "} {
+		if err := book.Add(AddressBookEntry{Address: guardLookAlike, Label: label}); !errors.Is(err, ErrInvalidLabel) {
+			t.Errorf("Add(label %q) expected ErrInvalidLabel, got %v", label, err)
+		}
+	}
+
+	// 重命名后旧标签失效
+	if err := book.Add(AddressBookEntry{Address: guardTrusted, Label: "Binance"}); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	if _, err := book.Resolve("exchange"); !errors.Is(err, ErrAddressNotFound) {
+		t.Errorf("Old label should be removed, got %v", err)
+	}
+	book.Remove(guardTrusted)
+	if _, err := book.Resolve("binance"); !errors.Is(err, ErrAddressNotFound) || book.Len() != 1 {
+		t.Errorf("Remove() failed: %v", err)
+	}
+
+	// 地址簿中的地址受投毒防护
+	guard := NewAddressGuard(0, 0)
+	guard.AddAddressBook(newTestAddressBook(t))
+	if err := guard.CheckAddress(guardLookAlike); !errors.Is(err, ErrAddressPoisoning) {
+		t.Errorf("Expected ErrAddressPoisoning, got %v", err)
+	}
+}
+
+func TestAddressBookPersistence(t *testing.T) {
+	book := newTestAddressBook(t)
+
+	var buf bytes.Buffer
+	if err := book.SaveJSON(&buf); err != nil {
+		t.Fatalf("SaveJSON() failed: %v", err)
+	}
+	loaded, err := LoadAddressBookJSON(&buf)
+	if err != nil {
+		t.Fatalf("LoadAddressBookJSON() failed: %v", err)
+	}
+	entry, ok := loaded.Get(guardTrusted)
+	if !ok || entry.Label != "Exchange" || !entry.HasTag("hot") || entry.Metadata[MainnetChainID]["memo"] != "12345" {
+		t.Errorf("JSON round trip mismatch: %+v", entry)
+	}
+
+	buf.Reset()
+	if err := book.SaveCSV(&buf); err != nil {
+		t.Fatalf("SaveCSV() failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "address,label,tags\n") {
+		t.Errorf("Unexpected CSV: %s", buf.String())
+	}
+	loaded, err = LoadAddressBookCSV(&buf)
+	if err != nil {
+		t.Fatalf("LoadAddressBookCSV() failed: %v", err)
+	}
+	entry, ok = loaded.Get(guardTrusted)
+	if !ok || entry.Label != "Exchange" || len(entry.Tags) != 2 {
+		t.Errorf("CSV round trip mismatch: %+v", entry)
+	}
+
+	// 无表头、小写地址
+	loaded, err = LoadAddressBookCSV(strings.NewReader(strings.ToLower(guardTrusted.Hex()) + ",Cold Wallet\n"))
+	if err != nil {
+		t.Fatalf("LoadAddressBookCSV() failed: %v", err)
+	}
+	if address, err := loaded.Resolve("cold wallet"); err != nil || address != guardTrusted {
+		t.Errorf("Resolve() = %s, %v", address.Hex(), err)
+	}
+	if _, err := LoadAddressBookCSV(strings.NewReader("not-an-address,label\n")); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("Expected ErrInvalidAddress, got %v", err)
+	}
+
+	// 校验和错误的大小写混合地址
+	if _, err := LoadAddressBookCSV(strings.NewReader(badChecksumHex(guardTrusted) + ",Cold Wallet\n")); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("Expected ErrInvalidAddress for bad checksum CSV, got %v", err)
+	}
+	if _, err := LoadAddressBookJSON(strings.NewReader(`[{"address":"` + badChecksumHex(guardTrusted) + `","label":"Cold Wallet"}]`)); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("Expected ErrInvalidAddress for bad checksum JSON, got %v", err)
+	}
+	loaded, err = LoadAddressBookJSON(strings.NewReader(`[{"address":"` + strings.ToLower(guardTrusted.Hex()) + `","label":"Cold Wallet"}]`))
+	if err != nil {
+		t.Fatalf("LoadAddressBookJSON() failed: %v", err)
+	}
+	if address, err := loaded.Resolve("cold wallet"); err != nil || address != guardTrusted {
+		t.Errorf("Resolve() = %s, %v", address.Hex(), err)
+	}
+
+	for _, name := range []string{"book.json", "book.csv"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := book.SaveFile(path); err != nil {
+			t.Fatalf("SaveFile(%s) failed: %v", name, err)
+		}
+		loaded, err := LoadAddressBookFile(path)
+		if err != nil {
+			t.Fatalf("LoadAddressBookFile(%s) failed: %v", name, err)
+		}
+		if loaded.Len() != book.Len() {
+			t.Errorf("%s: Len() = %d, expected %d", name, loaded.Len(), book.Len())
+		}
+	}
+}
+
+func TestWalletResolveAddress(t *testing.T) {
+	wallet, err := NewWalletWithComponents(nil, nil)
+	if err != nil {
+		t.Fatalf("NewWalletWithComponents() failed: %v", err)
+	}
+	if _, err := wallet.ResolveAddress("Exchange"); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("Expected ErrInvalidAddress without address book, got %v", err)
+	}
+
+	wallet.SetAddressBook(newTestAddressBook(t))
+	address, err := wallet.ResolveAddress("Exchange")
+	if err != nil || address != guardTrusted {
+		t.Errorf("ResolveAddress() = %s, %v", address.Hex(), err)
+	}
+	if _, err := wallet.SendTxTo("Nobody", 1, 21000, nil, nil, nil); !errors.Is(err, ErrAddressNotFound) {
+		t.Errorf("Expected ErrAddressNotFound, got %v", err)
+	}
+	if address, _ := wallet.ResolveAddress(common.Address{}.Hex()); address != (common.Address{}) {
+		t.Errorf("ResolveAddress(zero) = %s", address.Hex())
+	}
+
+	// 校验和错误的大小写混合地址
+	bad := badChecksumHex(guardTrusted)
+	if _, err := wallet.GetAddressBook().Resolve(bad); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("Resolve(%s) expected ErrInvalidAddress, got %v", bad, err)
+	}
+	if _, err := wallet.SendTxTo(bad, 1, 21000, nil, nil, nil); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("SendTxTo(%s) expected ErrInvalidAddress, got %v", bad, err)
+	}
+	wallet.SetAddressBook(nil)
+	if _, err := wallet.ResolveAddress(bad); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("ResolveAddress(%s) expected ErrInvalidAddress, got %v", bad, err)
+	}
+	if address, err := wallet.ResolveAddress(guardTrusted.Hex()); err != nil || address != guardTrusted {
+		t.Errorf("ResolveAddress() = %s, %v", address.Hex(), err)
+	}
+}
@@ -0,0 +1,239 @@
+package etherkit
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+//############ Address Poisoning ############
+
+// 默认按地址前4位和后4位十六进制字符判断相似，与大多数钱包界面的缩略显示一致
+const (
+	DefaultLookAlikePrefixLength = 4
+	DefaultLookAlikeSuffixLength = 4
+)
+
+// AddressRiskKind 地址风险类型
+type AddressRiskKind string
+
+const (
+	AddressRiskLookAlike       AddressRiskKind = "look-alike"
+	AddressRiskSpoofedTransfer AddressRiskKind = "spoofed-transfer"
+)
+
+// AddressWarning 地址风险提示
+type AddressWarning struct {
+	Kind      AddressRiskKind
+	Address   common.Address // 可疑地址
+	SimilarTo common.Address // 被模仿的已知地址，spoofed-transfer 时可能为空
+	Label     string         // 已知地址的标签
+	TxHash    common.Hash    // spoofed-transfer 所在交易
+}
+
+// String 风险的文字描述
+func (w AddressWarning) String() string {
+	switch w.Kind {
+	case AddressRiskLookAlike:
+		return fmt.Sprintf("%s looks like known address %s (%s)", w.Address.Hex(), w.SimilarTo.Hex(), w.Label)
+	case AddressRiskSpoofedTransfer:
+		return fmt.Sprintf("zero-value transfer involving %s in tx %s", w.Address.Hex(), w.TxHash.Hex())
+	default:
+		return string(w.Kind)
+	}
+}
+
+// PreSendCheck 发送交易前的检查，返回错误时交易不会被发送
+type PreSendCheck func(tx *types.Transaction) error
+
+// AddressGuard 根据可信地址和历史交易对手识别地址投毒：
+// 与已知地址前后缀相同但本身未知的地址会被视为仿冒地址
+type AddressGuard struct {
+	mu           sync.RWMutex
+	prefixLength int
+	suffixLength int
+	known        map[common.Address]string
+}
+
+// NewAddressGuard 创建 AddressGuard，prefixLength/suffixLength 为比较的十六进制字符数，传0使用默认值
+func NewAddressGuard(prefixLength, suffixLength int) *AddressGuard {
+	if prefixLength <= 0 {
+		prefixLength = DefaultLookAlikePrefixLength
+	}
+	if suffixLength <= 0 {
+		suffixLength = DefaultLookAlikeSuffixLength
+	}
+	// 前后缀各最多比较地址的一半
+	if prefixLength > common.AddressLength {
+		prefixLength = common.AddressLength
+	}
+	if suffixLength > common.AddressLength {
+		suffixLength = common.AddressLength
+	}
+	return &AddressGuard{
+		prefixLength: prefixLength,
+		suffixLength: suffixLength,
+		known:        make(map[common.Address]string),
+	}
+}
+
+// AddTrusted 添加可信地址（如地址簿中的交易对手）
+func (g *AddressGuard) AddTrusted(address common.Address, label string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.known[address] = label
+}
+
+// RecordCounterparty 记录曾经交互过的地址，已有标签的地址不会被覆盖
+func (g *AddressGuard) RecordCounterparty(address common.Address) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.known[address]; !ok {
+		g.known[address] = "counterparty"
+	}
+}
+
+// Remove 移除已知地址
+func (g *AddressGuard) Remove(address common.Address) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.known, address)
+}
+
+// IsKnown 是否是已知地址
+func (g *AddressGuard) IsKnown(address common.Address) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	_, ok := g.known[address]
+	return ok
+}
+
+// IsLookAlike 两个不同的地址是否前后缀相同（不区分大小写）
+func (g *AddressGuard) IsLookAlike(a, b common.Address) bool {
+	if a == b {
+		return false
+	}
+	ha, hb := hex.EncodeToString(a.Bytes()), hex.EncodeToString(b.Bytes())
+	return ha[:g.prefixLength] == hb[:g.prefixLength] &&
+		ha[len(ha)-g.suffixLength:] == hb[len(hb)-g.suffixLength:]
+}
+
+// Inspect 检查目标地址，已知地址返回 nil，否则返回所有被模仿的已知地址
+func (g *AddressGuard) Inspect(address common.Address) []AddressWarning {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if _, ok := g.known[address]; ok {
+		return nil
+	}
+
+	var warnings []AddressWarning
+	for known, label := range g.known {
+		if g.IsLookAlike(address, known) {
+			warnings = append(warnings, AddressWarning{
+				Kind:      AddressRiskLookAlike,
+				Address:   address,
+				SimilarTo: known,
+				Label:     label,
+			})
+		}
+	}
+	return warnings
+}
+
+// CheckAddress 目标地址仿冒已知地址时返回 ErrAddressPoisoning
+func (g *AddressGuard) CheckAddress(address common.Address) error {
+	if warnings := g.Inspect(address); len(warnings) > 0 {
+		return errors.Wrap(ErrAddressPoisoning, warnings[0].String())
+	}
+	return nil
+}
+
+// CheckTx 检查交易的接收者，以及 ERC20 transfer/transferFrom/approve 调用中的收款或授权地址
+func (g *AddressGuard) CheckTx(tx *types.Transaction) error {
+	if tx.To() != nil {
+		if err := g.CheckAddress(*tx.To()); err != nil {
+			return err
+		}
+	}
+	if recipient, ok := DecodeTokenRecipient(tx.Data()); ok {
+		return g.CheckAddress(recipient)
+	}
+	return nil
+}
+
+// PreSendCheck 返回可以注册到 Wallet.AddPreSendCheck 的检查函数
+func (g *AddressGuard) PreSendCheck() PreSendCheck {
+	return g.CheckTx
+}
+
+// InspectTransferLogs 检查与 owner 相关的 ERC20 Transfer 日志：
+// 金额为0的转账和来自/发往仿冒地址的转账都会被标记，这类记录不应被当作真实交易对手
+func (g *AddressGuard) InspectTransferLogs(owner common.Address, logs []*types.Log) []AddressWarning {
+	var warnings []AddressWarning
+	for _, log := range logs {
+		from, to, value, ok := decodeTransferLog(log)
+		if !ok || (from != owner && to != owner) {
+			continue
+		}
+
+		counterparty := to
+		if to == owner {
+			counterparty = from
+		}
+		if value.Sign() == 0 {
+			warnings = append(warnings, AddressWarning{
+				Kind:    AddressRiskSpoofedTransfer,
+				Address: counterparty,
+				TxHash:  log.TxHash,
+			})
+		}
+		for _, w := range g.Inspect(counterparty) {
+			w.TxHash = log.TxHash
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// DecodeTokenRecipient 从 ERC20 transfer/transferFrom/approve 的 calldata 中解析收款或授权地址
+func DecodeTokenRecipient(data []byte) (common.Address, bool) {
+	if len(data) < 4 {
+		return common.Address{}, false
+	}
+
+	var offset int
+	switch hexutil.Encode(data[:4]) {
+	case ERC20TransferMethodID, ERC20ApproveMethodID:
+		offset = 4
+	case ERC20TransferFromMethodID:
+		offset = 4 + 32
+	default:
+		return common.Address{}, false
+	}
+	return decodeAddressWord(data, offset)
+}
+
+// decodeTransferLog 解析标准 ERC20 Transfer(address indexed, address indexed, uint256) 日志
+func decodeTransferLog(log *types.Log) (from, to common.Address, value *big.Int, ok bool) {
+	if len(log.Topics) != 3 || log.Topics[0] != common.HexToHash(ERC20TransferEventTopic) || len(log.Data) != 32 {
+		return from, to, nil, false
+	}
+	from = common.BytesToAddress(log.Topics[1].Bytes())
+	to = common.BytesToAddress(log.Topics[2].Bytes())
+	return from, to, new(big.Int).SetBytes(log.Data), true
+}
+
+// decodeAddressWord 读取 ABI 编码中 offset 处的地址，高12字节必须为0
+func decodeAddressWord(data []byte, offset int) (common.Address, bool) {
+	if len(data) < offset+32 || !bytes.Equal(data[offset:offset+12], make([]byte, 12)) {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(data[offset+12 : offset+32]), true
+}
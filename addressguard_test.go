@@ -0,0 +1,138 @@
+package etherkit
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	guardTrusted   = common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	guardLookAlike = common.HexToAddress("0x7099000000000000000000000000000000d179c8")
+	guardUnrelated = common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+)
+
+func TestAddressGuardCheckAddress(t *testing.T) {
+	guard := NewAddressGuard(0, 0)
+	guard.AddTrusted(guardTrusted, "exchange")
+
+	if err := guard.CheckAddress(guardTrusted); err != nil {
+		t.Errorf("Trusted address should pass: %v", err)
+	}
+	if err := guard.CheckAddress(guardUnrelated); err != nil {
+		t.Errorf("Unrelated address should pass: %v", err)
+	}
+	if err := guard.CheckAddress(guardLookAlike); !errors.Is(err, ErrAddressPoisoning) {
+		t.Errorf("Expected ErrAddressPoisoning, got %v", err)
+	}
+
+	warnings := guard.Inspect(guardLookAlike)
+	if len(warnings) != 1 || warnings[0].SimilarTo != guardTrusted || warnings[0].Label != "exchange" {
+		t.Errorf("Unexpected warnings: %v", warnings)
+	}
+
+	// 更严格的比较长度不再命中
+	strict := NewAddressGuard(6, 6)
+	strict.AddTrusted(guardTrusted, "exchange")
+	if err := strict.CheckAddress(guardLookAlike); err != nil {
+		t.Errorf("Unexpected error with longer prefix: %v", err)
+	}
+
+	// 历史交易对手同样受保护
+	history := NewAddressGuard(0, 0)
+	history.RecordCounterparty(guardTrusted)
+	if err := history.CheckAddress(guardLookAlike); !errors.Is(err, ErrAddressPoisoning) {
+		t.Errorf("Expected ErrAddressPoisoning, got %v", err)
+	}
+}
+
+func TestAddressGuardCheckTx(t *testing.T) {
+	guard := NewAddressGuard(0, 0)
+	guard.AddTrusted(guardTrusted, "exchange")
+
+	token := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	transfer := append(common.FromHex(ERC20TransferMethodID), common.LeftPadBytes(guardLookAlike.Bytes(), 32)...)
+	transfer = append(transfer, common.LeftPadBytes(big.NewInt(1).Bytes(), 32)...)
+	recipient, ok := DecodeTokenRecipient(transfer)
+	if !ok || recipient != guardLookAlike {
+		t.Errorf("DecodeTokenRecipient() = %s, %v", recipient.Hex(), ok)
+	}
+
+	tests := []struct {
+		name   string
+		to     common.Address
+		data   []byte
+		target error
+	}{
+		{"Native transfer to trusted", guardTrusted, nil, nil},
+		{"Native transfer to look-alike", guardLookAlike, nil, ErrAddressPoisoning},
+		{"Token transfer to look-alike", token, transfer, ErrAddressPoisoning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := types.NewTx(&types.LegacyTx{To: &tt.to, Data: tt.data, Gas: 21000, GasPrice: big.NewInt(1)})
+			err := guard.PreSendCheck()(tx)
+			if !errors.Is(err, tt.target) || (tt.target == nil && err != nil) {
+				t.Errorf("Expected %v, got %v", tt.target, err)
+			}
+		})
+	}
+
+	// 钱包在发送前执行检查
+	signer, err := NewSignerFromHexPrivateKey(signerTestHexPk)
+	if err != nil {
+		t.Fatalf("NewSignerFromHexPrivateKey() failed: %v", err)
+	}
+	wallet, err := NewWalletWithComponents(signer, nil)
+	if err != nil {
+		t.Fatalf("NewWalletWithComponents() failed: %v", err)
+	}
+	wallet.AddPreSendCheck(guard.PreSendCheck())
+	tx := types.NewTx(&types.LegacyTx{To: &guardLookAlike, Gas: 21000, GasPrice: big.NewInt(1)})
+	if _, err := wallet.SendSignedTx(tx); !errors.Is(err, ErrAddressPoisoning) {
+		t.Errorf("Expected ErrAddressPoisoning, got %v", err)
+	}
+}
+
+func TestAddressGuardInspectTransferLogs(t *testing.T) {
+	guard := NewAddressGuard(0, 0)
+	guard.AddTrusted(guardTrusted, "exchange")
+	owner := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+	transferLog := func(from, to common.Address, value int64, txHash common.Hash) *types.Log {
+		return &types.Log{
+			Topics: []common.Hash{
+				common.HexToHash(ERC20TransferEventTopic),
+				common.BytesToHash(from.Bytes()),
+				common.BytesToHash(to.Bytes()),
+			},
+			Data:   common.BigToHash(big.NewInt(value)).Bytes(),
+			TxHash: txHash,
+		}
+	}
+
+	logs := []*types.Log{
+		transferLog(owner, guardTrusted, 100, common.HexToHash("0x01")),
+		transferLog(owner, guardLookAlike, 0, common.HexToHash("0x02")),
+		transferLog(guardLookAlike, owner, 1, common.HexToHash("0x03")),
+		transferLog(guardUnrelated, guardLookAlike, 0, common.HexToHash("0x04")),
+	}
+
+	warnings := guard.InspectTransferLogs(owner, logs)
+	kinds := map[common.Hash][]AddressRiskKind{}
+	for _, w := range warnings {
+		kinds[w.TxHash] = append(kinds[w.TxHash], w.Kind)
+	}
+	if len(kinds[common.HexToHash("0x01")]) != 0 || len(kinds[common.HexToHash("0x04")]) != 0 {
+		t.Errorf("Unexpected warnings: %v", warnings)
+	}
+	if len(kinds[common.HexToHash("0x02")]) != 2 {
+		t.Errorf("Expected spoofed-transfer and look-alike warnings for 0x02, got %v", kinds[common.HexToHash("0x02")])
+	}
+	if k := kinds[common.HexToHash("0x03")]; len(k) != 1 || k[0] != AddressRiskLookAlike {
+		t.Errorf("Expected look-alike warning for 0x03, got %v", k)
+	}
+}
@@ -0,0 +1,229 @@
+package etherkit
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+//############ Bound Contract ############
+
+// BoundContract 在运行时由 ABI 驱动的合约对象，无需 abigen 生成绑定代码
+type BoundContract struct {
+	address common.Address
+	abi     abi.ABI
+	wallet  EtherWallet
+}
+
+// TransactOptions 合约写操作的选项，零值字段沿用 Wallet.NewTx 的自动计算逻辑
+type TransactOptions struct {
+	Value    *big.Int // 随交易发送的本位币
+	Nonce    uint64   // 0表示使用 pending nonce
+	GasLimit uint64   // 0表示自动估算
+	GasPrice *big.Int // nil表示使用建议的 gas price
+}
+
+// ContractEvent 解码后的合约事件
+type ContractEvent struct {
+	Name   string                 // 事件名
+	Fields map[string]interface{} // 参数名到值，规则同 DecodedLog.Fields
+	Log    types.Log              // 原始日志
+}
+
+// NewBoundContract 创建合约对象
+func NewBoundContract(address common.Address, contractAbi abi.ABI, wallet EtherWallet) (*BoundContract, error) {
+	if address == (common.Address{}) {
+		return nil, errors.Wrap(ErrInvalidContractAddress, "contract address is zero")
+	}
+	if wallet == nil {
+		return nil, errors.Wrap(ErrInvalidWalletConfig, "wallet is nil")
+	}
+	return &BoundContract{address: address, abi: contractAbi, wallet: wallet}, nil
+}
+
+// Address 合约地址
+func (c *BoundContract) Address() common.Address {
+	return c.address
+}
+
+// ABI 合约ABI
+func (c *BoundContract) ABI() abi.ABI {
+	return c.abi
+}
+
+// Pack 构建方法的 input data
+func (c *BoundContract) Pack(method string, args ...interface{}) ([]byte, error) {
+	if _, ok := c.abi.Methods[method]; !ok {
+		return nil, errors.Wrap(ErrMethodNotFound, method)
+	}
+	return BuildContractInputData(c.abi, method, args...)
+}
+
+// Call 以钱包地址为 from 调用只读方法，返回解码后的结果
+func (c *BoundContract) Call(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
+	data, err := c.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.wallet.GetClient().CallContract(ctx, ethereum.CallMsg{
+		From: c.wallet.GetAddress(),
+		To:   &c.address,
+		Data: data,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", method, err, ErrContractCall)
+	}
+	return c.abi.Unpack(method, res)
+}
+
+// EstimateGas 预估以钱包地址调用方法所需的 gas
+func (c *BoundContract) EstimateGas(ctx context.Context, method string, args ...interface{}) (uint64, error) {
+	data, err := c.Pack(method, args...)
+	if err != nil {
+		return 0, err
+	}
+	return c.wallet.GetClient().EstimateGas(ctx, ethereum.CallMsg{
+		From: c.wallet.GetAddress(),
+		To:   &c.address,
+		Data: data,
+	})
+}
+
+// Transact 签名并发送调用方法的交易，返回已签名的交易。opts 可以为 nil。
+// ctx 用于获取 nonce、gas price 和估算 gas，发送前 ctx 已取消时不会发送交易
+func (c *BoundContract) Transact(ctx context.Context, method string, opts *TransactOptions, args ...interface{}) (*types.Transaction, error) {
+	if opts == nil {
+		opts = &TransactOptions{}
+	}
+	data, err := c.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	client := c.wallet.GetClient()
+	nonce := opts.Nonce
+	if nonce == 0 {
+		if nonce, err = client.PendingNonceAt(ctx, c.wallet.GetAddress()); err != nil {
+			return nil, err
+		}
+	}
+	gasPrice := opts.GasPrice
+	if gasPrice == nil || gasPrice.Sign() == 0 {
+		if gasPrice, err = client.SuggestGasPrice(ctx); err != nil {
+			return nil, err
+		}
+	}
+	gasLimit := opts.GasLimit
+	if gasLimit == 0 {
+		gasLimit, err = client.EstimateGas(ctx, ethereum.CallMsg{
+			From:     c.wallet.GetAddress(),
+			To:       &c.address,
+			GasPrice: gasPrice,
+			Value:    opts.Value,
+			Data:     data,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := NewTx(c.address, nonce, gasLimit, gasPrice, opts.Value, data)
+	if err != nil {
+		return nil, err
+	}
+	signedTx, err := c.wallet.SignTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if _, err := c.wallet.SendSignedTx(signedTx); err != nil {
+		return nil, err
+	}
+	return signedTx, nil
+}
+
+// FilterEvents 查询区块范围内的事件并解码。toBlock 为 nil 表示最新区块；
+// indexed 按顺序对应事件的 indexed 参数，每一项为可选值列表，nil 表示不过滤
+func (c *BoundContract) FilterEvents(ctx context.Context, eventName string, fromBlock, toBlock *big.Int, indexed ...[]interface{}) ([]*ContractEvent, error) {
+	event, ok := c.abi.Events[eventName]
+	if !ok {
+		return nil, errors.Wrap(ErrEventNotFound, eventName)
+	}
+
+	topics, err := abi.MakeTopics(indexed...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build topics for %s", eventName)
+	}
+	if !event.Anonymous {
+		topics = append([][]common.Hash{{event.ID}}, topics...)
+	}
+
+	logs, err := c.wallet.GetClient().FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: []common.Address{c.address},
+		Topics:    topics,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*ContractEvent, 0, len(logs))
+	for _, log := range logs {
+		decoded, err := c.DecodeEvent(eventName, log)
+		if err != nil {
+			// 匿名事件只能按 indexed 参数过滤，可能混入其他事件的日志
+			if event.Anonymous && errors.Is(err, ErrEventMismatch) {
+				continue
+			}
+			return nil, err
+		}
+		events = append(events, decoded)
+	}
+	return events, nil
+}
+
+// DecodeEvent 按事件名解码日志，日志与事件不匹配时返回 ErrEventMismatch
+func (c *BoundContract) DecodeEvent(eventName string, log types.Log) (*ContractEvent, error) {
+	event, ok := c.abi.Events[eventName]
+	if !ok {
+		return nil, errors.Wrap(ErrEventNotFound, eventName)
+	}
+
+	values, err := unpackEventLog(event, &log)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractEvent{Name: eventName, Fields: newDecodedLog(event, values, &log).Fields, Log: log}, nil
+}
+
+// DecodeEvents 解码日志中属于本合约的指定事件，跳过其他地址或其他事件的日志
+func (c *BoundContract) DecodeEvents(eventName string, logs []*types.Log) ([]*ContractEvent, error) {
+	if _, ok := c.abi.Events[eventName]; !ok {
+		return nil, errors.Wrap(ErrEventNotFound, eventName)
+	}
+	var events []*ContractEvent
+	for _, log := range logs {
+		if log == nil || log.Address != c.address {
+			continue
+		}
+		decoded, err := c.DecodeEvent(eventName, *log)
+		if errors.Is(err, ErrEventMismatch) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, decoded)
+	}
+	return events, nil
+}
@@ -0,0 +1,163 @@
+package etherkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/guanzhenxing/go-evm-kit/contracts/erc20"
+)
+
+func newTestBoundContract(t *testing.T, address common.Address) *BoundContract {
+	contractAbi, err := erc20.IERC20MetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("GetAbi() failed: %v", err)
+	}
+	wallet, err := NewWalletWithComponents(nil, nil)
+	if err != nil {
+		t.Fatalf("NewWalletWithComponents() failed: %v", err)
+	}
+	contract, err := NewBoundContract(address, *contractAbi, wallet)
+	if err != nil {
+		t.Fatalf("NewBoundContract() failed: %v", err)
+	}
+	return contract
+}
+
+func TestBoundContractPack(t *testing.T) {
+	contract := newTestBoundContract(t, guardUnrelated)
+
+	data, err := contract.Pack("transfer", guardTrusted, big.NewInt(1))
+	if err != nil {
+		t.Fatalf("Pack() failed: %v", err)
+	}
+	if recipient, ok := DecodeTokenRecipient(data); !ok || recipient != guardTrusted {
+		t.Errorf("Unexpected calldata: %x", data)
+	}
+	if _, err := contract.Pack("mint", guardTrusted); !errors.Is(err, ErrMethodNotFound) {
+		t.Errorf("Expected ErrMethodNotFound, got %v", err)
+	}
+	if _, err := contract.Transact(context.Background(), "mint", nil, guardTrusted); !errors.Is(err, ErrMethodNotFound) {
+		t.Errorf("Expected ErrMethodNotFound, got %v", err)
+	}
+	if _, err := NewBoundContract(common.Address{}, contract.ABI(), nil); !errors.Is(err, ErrInvalidContractAddress) {
+		t.Errorf("Expected ErrInvalidContractAddress, got %v", err)
+	}
+}
+
+func TestBoundContractDecodeEvent(t *testing.T) {
+	contract := newTestBoundContract(t, guardUnrelated)
+
+	transfer := &types.Log{
+		Address: guardUnrelated,
+		Topics: []common.Hash{
+			common.HexToHash(ERC20TransferEventTopic),
+			common.BytesToHash(guardTrusted.Bytes()),
+			common.BytesToHash(guardLookAlike.Bytes()),
+		},
+		Data: common.BigToHash(big.NewInt(100)).Bytes(),
+	}
+
+	event, err := contract.DecodeEvent("Transfer", *transfer)
+	if err != nil {
+		t.Fatalf("DecodeEvent() failed: %v", err)
+	}
+	if event.Fields["from"] != guardTrusted || event.Fields["to"] != guardLookAlike || event.Fields["value"].(*big.Int).Int64() != 100 {
+		t.Errorf("Unexpected fields: %v", event.Fields)
+	}
+
+	if _, err := contract.DecodeEvent("Approval", *transfer); !errors.Is(err, ErrEventMismatch) {
+		t.Errorf("Expected ErrEventMismatch, got %v", err)
+	}
+	if _, err := contract.DecodeEvent("Deposit", *transfer); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("Expected ErrEventNotFound, got %v", err)
+	}
+
+	// 其他合约的日志被跳过
+	other := *transfer
+	other.Address = guardTrusted
+	events, err := contract.DecodeEvents("Transfer", []*types.Log{transfer, &other})
+	if err != nil || len(events) != 1 {
+		t.Errorf("DecodeEvents() = %d events, %v", len(events), err)
+	}
+}
+
+// boundContractBlockingService 阻塞所有请求直到测试结束，用于确认 ctx 被传递给 RPC 调用
+type boundContractBlockingService struct {
+	done chan struct{}
+}
+
+func (s *boundContractBlockingService) Call(args map[string]interface{}, block *json.RawMessage) (hexutil.Bytes, error) {
+	<-s.done
+	return nil, nil
+}
+
+func (s *boundContractBlockingService) EstimateGas(args map[string]interface{}, block *json.RawMessage) (hexutil.Uint64, error) {
+	<-s.done
+	return 0, nil
+}
+
+func (s *boundContractBlockingService) GetTransactionCount(address common.Address, block *json.RawMessage) (hexutil.Uint64, error) {
+	<-s.done
+	return 0, nil
+}
+
+func newBoundContractBlockingWallet(t *testing.T) *Wallet {
+	service := &boundContractBlockingService{done: make(chan struct{})}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatalf("RegisterName() failed: %v", err)
+	}
+	client := rpc.DialInProc(server)
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+	t.Cleanup(func() { close(service.done) })
+
+	key, err := GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("GeneratePrivateKey() failed: %v", err)
+	}
+	signer, err := NewSignerFromPrivateKey(key)
+	if err != nil {
+		t.Fatalf("NewSignerFromPrivateKey() failed: %v", err)
+	}
+	wallet, err := NewWalletWithComponents(signer, &Provider{rc: client, ec: ethclient.NewClient(client)})
+	if err != nil {
+		t.Fatalf("NewWalletWithComponents() failed: %v", err)
+	}
+	return wallet
+}
+
+func TestBoundContractContext(t *testing.T) {
+	contractAbi, err := erc20.IERC20MetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("GetAbi() failed: %v", err)
+	}
+	contract, err := NewBoundContract(guardUnrelated, *contractAbi, newBoundContractBlockingWallet(t))
+	if err != nil {
+		t.Fatalf("NewBoundContract() failed: %v", err)
+	}
+
+	// RPC 节点不响应时，调用在 ctx 超时后返回
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := CallAs[*big.Int](ctx, contract, "balanceOf", guardTrusted); !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, ErrContractCall) {
+		t.Errorf("CallAs() expected context.DeadlineExceeded and ErrContractCall, got %v", err)
+	}
+	if _, err := contract.EstimateGas(ctx, "transfer", guardTrusted, big.NewInt(1)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("EstimateGas() expected context.DeadlineExceeded, got %v", err)
+	}
+	if _, err := contract.Transact(ctx, "transfer", nil, guardTrusted, big.NewInt(1)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Transact() expected context.DeadlineExceeded, got %v", err)
+	}
+}
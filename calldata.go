@@ -0,0 +1,325 @@
+package etherkit
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+//############ Calldata Decoder ############
+
+// maxNestedCallDepth 嵌套调用的最大解码深度
+const maxNestedCallDepth = 8
+
+// nestedCallsABI Multicall/Multicall3、Uniswap 风格的 multicall 以及 Safe 的 execTransaction、multiSend
+var nestedCallsABI = mustParseABI(`[
+	{"type":"function","name":"aggregate","inputs":[{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"callData","type":"bytes"}]}],"outputs":[]},
+	{"type":"function","name":"tryAggregate","inputs":[{"name":"requireSuccess","type":"bool"},{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"callData","type":"bytes"}]}],"outputs":[]},
+	{"type":"function","name":"blockAndAggregate","inputs":[{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"callData","type":"bytes"}]}],"outputs":[]},
+	{"type":"function","name":"tryBlockAndAggregate","inputs":[{"name":"requireSuccess","type":"bool"},{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"callData","type":"bytes"}]}],"outputs":[]},
+	{"type":"function","name":"aggregate3","inputs":[{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}]}],"outputs":[]},
+	{"type":"function","name":"aggregate3Value","inputs":[{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"value","type":"uint256"},{"name":"callData","type":"bytes"}]}],"outputs":[]},
+	{"type":"function","name":"multicall","inputs":[{"name":"data","type":"bytes[]"}],"outputs":[]},
+	{"type":"function","name":"multicall","inputs":[{"name":"deadline","type":"uint256"},{"name":"data","type":"bytes[]"}],"outputs":[]},
+	{"type":"function","name":"multicall","inputs":[{"name":"previousBlockhash","type":"bytes32"},{"name":"data","type":"bytes[]"}],"outputs":[]},
+	{"type":"function","name":"execTransaction","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},{"name":"operation","type":"uint8"},{"name":"safeTxGas","type":"uint256"},{"name":"baseGas","type":"uint256"},{"name":"gasPrice","type":"uint256"},{"name":"gasToken","type":"address"},{"name":"refundReceiver","type":"address"},{"name":"signatures","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"multiSend","inputs":[{"name":"transactions","type":"bytes"}],"outputs":[]}
+]`)
+
+// DecodedArg 解码后的方法参数
+type DecodedArg struct {
+	Name  string
+	Type  string
+	Value interface{}
+}
+
+// DecodedCall 解码后的合约调用
+type DecodedCall struct {
+	Method    string        // 方法名
+	Signature string        // 方法签名，如 transfer(address,uint256)
+	Selector  string        // 4字节选择器，如 0xa9059cbb
+	Args      []DecodedArg  // 按顺序排列的参数
+	Nested    []*NestedCall // multicall、Safe 交易等包含的内部调用
+}
+
+// NestedCall multicall 或 Safe 交易中的一个内部调用
+type NestedCall struct {
+	To           common.Address // 目标地址；Uniswap 风格的 multicall 调用自身，To 为外层合约地址（未知时为零地址）
+	Value        *big.Int
+	Operation    uint8 // Safe 的操作类型：0 为 call，1 为 delegatecall
+	AllowFailure bool
+	Data         []byte
+	Call         *DecodedCall // 无法识别选择器时为 nil
+}
+
+// CalldataDecoder 根据一个或多个 ABI 解码交易 calldata，并递归解码 multicall 和 Safe 交易中的内部调用。
+// 并发安全
+type CalldataDecoder struct {
+	mu       sync.RWMutex
+	abis     []abi.ABI
+	registry *SignatureRegistry
+}
+
+// NewCalldataDecoder 创建 calldata 解码器，内置了常见的 multicall 和 Safe 方法
+func NewCalldataDecoder(abis ...abi.ABI) *CalldataDecoder {
+	return &CalldataDecoder{abis: append([]abi.ABI(nil), abis...)}
+}
+
+// AddABI 添加 ABI，先添加的 ABI 优先匹配
+func (d *CalldataDecoder) AddABI(contractAbi abi.ABI) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.abis = append(d.abis, contractAbi)
+}
+
+// SetSignatureRegistry 设置签名库，ABI 中找不到选择器时使用签名库中第一个能够解码的候选签名（参数没有名字）
+func (d *CalldataDecoder) SetSignatureRegistry(registry *SignatureRegistry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.registry = registry
+}
+
+// DecodeCalldata 使用 ABI 解码 calldata，并递归解码其中的 multicall 和 Safe 内部调用
+func DecodeCalldata(contractAbi abi.ABI, data []byte) (*DecodedCall, error) {
+	return NewCalldataDecoder(contractAbi).Decode(data)
+}
+
+// Decode 解码 calldata，选择器未知时返回 ErrMethodNotFound
+func (d *CalldataDecoder) Decode(data []byte) (*DecodedCall, error) {
+	return d.decode(common.Address{}, data, 0)
+}
+
+// DecodeTx 解码交易的 calldata，自调用的 multicall 内部调用以交易的 to 为目标地址
+func (d *CalldataDecoder) DecodeTx(tx *types.Transaction) (*DecodedCall, error) {
+	var to common.Address
+	if tx.To() != nil {
+		to = *tx.To()
+	}
+	return d.decode(to, tx.Data(), 0)
+}
+
+func (d *CalldataDecoder) decode(to common.Address, data []byte, depth int) (*DecodedCall, error) {
+	if len(data) < 4 {
+		return nil, errors.Wrapf(ErrInvalidCalldata, "calldata is %d bytes", len(data))
+	}
+	call, err := d.decodeCall(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if depth < maxNestedCallDepth {
+		values := make([]interface{}, len(call.Args))
+		for i, arg := range call.Args {
+			values[i] = arg.Value
+		}
+		call.Nested = nestedCalls(call.Signature, to, values)
+		for _, nested := range call.Nested {
+			// 内部调用无法识别时保留原始数据
+			nested.Call, _ = d.decode(nested.To, nested.Data, depth+1)
+		}
+	}
+	return call, nil
+}
+
+// decodeCall 按 ABI、内置方法、签名库的顺序解码调用本身
+func (d *CalldataDecoder) decodeCall(data []byte) (*DecodedCall, error) {
+	d.mu.RLock()
+	abis, registry := d.abis, d.registry
+	d.mu.RUnlock()
+
+	var method *abi.Method
+	for _, a := range abis {
+		if m, err := a.MethodById(data[:4]); err == nil {
+			method = m
+			break
+		}
+	}
+	if method == nil {
+		method, _ = nestedCallsABI.MethodById(data[:4])
+	}
+	if method == nil {
+		if registry == nil {
+			return nil, errors.Wrapf(ErrMethodNotFound, "selector %s", hexutil.Encode(data[:4]))
+		}
+		calls, err := registry.DecodeCalldata(data)
+		if err != nil {
+			return nil, err
+		}
+		return calls[0], nil
+	}
+
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidCalldata, "%s: %v", method.Sig, err)
+	}
+	call := &DecodedCall{
+		Method:    method.RawName,
+		Signature: method.Sig,
+		Selector:  hexutil.Encode(method.ID),
+		Args:      make([]DecodedArg, len(values)),
+	}
+	for i, input := range method.Inputs {
+		call.Args[i] = DecodedArg{Name: input.Name, Type: input.Type.String(), Value: values[i]}
+	}
+	return call, nil
+}
+
+// nestedCalls 按方法签名提取内部调用，tuple 字段按位置读取，与 ABI 中的组件名无关
+func nestedCalls(signature string, self common.Address, values []interface{}) []*NestedCall {
+	var calls []*NestedCall
+	switch signature {
+	case "aggregate((address,bytes)[])", "blockAndAggregate((address,bytes)[])",
+		"tryAggregate(bool,(address,bytes)[])", "tryBlockAndAggregate(bool,(address,bytes)[])":
+		forEachTuple(values[len(values)-1], func(t reflect.Value) {
+			calls = append(calls, &NestedCall{To: t.Field(0).Interface().(common.Address), Data: t.Field(1).Bytes()})
+		})
+	case "aggregate3((address,bool,bytes)[])":
+		forEachTuple(values[0], func(t reflect.Value) {
+			calls = append(calls, &NestedCall{To: t.Field(0).Interface().(common.Address), AllowFailure: t.Field(1).Bool(), Data: t.Field(2).Bytes()})
+		})
+	case "aggregate3Value((address,bool,uint256,bytes)[])":
+		forEachTuple(values[0], func(t reflect.Value) {
+			calls = append(calls, &NestedCall{
+				To:           t.Field(0).Interface().(common.Address),
+				AllowFailure: t.Field(1).Bool(),
+				Value:        t.Field(2).Interface().(*big.Int),
+				Data:         t.Field(3).Bytes(),
+			})
+		})
+	case "multicall(bytes[])", "multicall(uint256,bytes[])", "multicall(bytes32,bytes[])":
+		for _, data := range values[len(values)-1].([][]byte) {
+			calls = append(calls, &NestedCall{To: self, Data: data})
+		}
+	case "execTransaction(address,uint256,bytes,uint8,uint256,uint256,uint256,address,address,bytes)":
+		calls = append(calls, &NestedCall{
+			To:        values[0].(common.Address),
+			Value:     values[1].(*big.Int),
+			Data:      values[2].([]byte),
+			Operation: values[3].(uint8),
+		})
+	case "multiSend(bytes)":
+		calls = decodeMultiSend(values[0].([]byte))
+	}
+	return calls
+}
+
+// decodeMultiSend 解码 Safe MultiSend 的打包交易：operation(1) ‖ to(20) ‖ value(32) ‖ dataLength(32) ‖ data
+func decodeMultiSend(packed []byte) []*NestedCall {
+	var calls []*NestedCall
+	for len(packed) >= 85 {
+		length := new(big.Int).SetBytes(packed[53:85])
+		if !length.IsUint64() || length.Uint64() > uint64(len(packed)-85) {
+			break
+		}
+		end := 85 + int(length.Uint64())
+		calls = append(calls, &NestedCall{
+			Operation: packed[0],
+			To:        common.BytesToAddress(packed[1:21]),
+			Value:     new(big.Int).SetBytes(packed[21:53]),
+			Data:      common.CopyBytes(packed[85:end]),
+		})
+		packed = packed[end:]
+	}
+	return calls
+}
+
+func forEachTuple(slice interface{}, fn func(reflect.Value)) {
+	v := reflect.ValueOf(slice)
+	for i := 0; i < v.Len(); i++ {
+		fn(v.Index(i))
+	}
+}
+
+//############ Calldata Formatting ############
+
+// String 以多行文本输出调用及其内部调用
+func (c *DecodedCall) String() string {
+	return c.Format(nil)
+}
+
+// Format 以多行文本输出调用及其内部调用，地址通过 labeler 显示标签，labeler 可以为 nil
+func (c *DecodedCall) Format(labeler AddressLabeler) string {
+	var b strings.Builder
+	c.write(&b, labeler, "")
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (c *DecodedCall) write(b *strings.Builder, labeler AddressLabeler, indent string) {
+	fmt.Fprintf(b, "%s%s %s\n", indent, c.Signature, c.Selector)
+	for i, arg := range c.Args {
+		fmt.Fprintf(b, "%s  %s (%s): %s\n", indent, eventFieldName(arg.Name, i), arg.Type, formatABIValue(reflect.ValueOf(arg.Value), labeler))
+	}
+	for i, nested := range c.Nested {
+		fmt.Fprintf(b, "%s  [%d] to %s", indent, i, FormatAddress(nested.To, labeler))
+		if nested.Value != nil && nested.Value.Sign() > 0 {
+			fmt.Fprintf(b, " value %s", nested.Value)
+		}
+		if nested.Operation == 1 {
+			b.WriteString(" (delegatecall)")
+		}
+		b.WriteString("\n")
+		if nested.Call != nil {
+			nested.Call.write(b, labeler, indent+"    ")
+		} else if len(nested.Data) > 0 {
+			fmt.Fprintf(b, "%s    %s\n", indent, hexutil.Encode(nested.Data))
+		}
+	}
+}
+
+// formatABIValue 格式化 abi 解码得到的值：地址带标签，字节以十六进制输出，tuple 输出字段名
+func formatABIValue(v reflect.Value, labeler AddressLabeler) string {
+	if !v.IsValid() {
+		return "<nil>"
+	}
+	switch value := v.Interface().(type) {
+	case common.Address:
+		return FormatAddress(value, labeler)
+	case *big.Int:
+		return value.String()
+	case []byte:
+		return hexutil.Encode(value)
+	}
+
+	switch v.Kind() {
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return hexutil.Encode(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatABIValue(v.Index(i), labeler)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Struct:
+		fields := make([]string, v.NumField())
+		for i := range fields {
+			name := v.Type().Field(i).Tag.Get("json")
+			if name == "" {
+				name = v.Type().Field(i).Name
+			}
+			fields[i] = name + ": " + formatABIValue(v.Field(i), labeler)
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func mustParseABI(s string) abi.ABI {
+	a, err := GetABI(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
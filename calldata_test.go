@@ -0,0 +1,121 @@
+package etherkit
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/guanzhenxing/go-evm-kit/contracts/erc20"
+)
+
+func TestDecodeCalldata(t *testing.T) {
+	erc20Abi, err := erc20.IERC20MetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("GetAbi() failed: %v", err)
+	}
+	transfer, err := erc20Abi.Pack("transfer", guardTrusted, big.NewInt(1000))
+	if err != nil {
+		t.Fatalf("Pack() failed: %v", err)
+	}
+
+	call, err := DecodeCalldata(*erc20Abi, transfer)
+	if err != nil {
+		t.Fatalf("DecodeCalldata() failed: %v", err)
+	}
+	if call.Signature != "transfer(address,uint256)" || call.Selector != ERC20TransferMethodID ||
+		call.Args[0].Value != guardTrusted || call.Args[1].Value.(*big.Int).Int64() != 1000 {
+		t.Errorf("Unexpected call: %+v", call)
+	}
+
+	book := NewAddressBook()
+	if err := book.Add(AddressBookEntry{Address: guardTrusted, Label: "Exchange"}); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	expected := "transfer(address,uint256) 0xa9059cbb\n" +
+		"  to (address): Exchange (" + guardTrusted.Hex() + ")\n" +
+		"  amount (uint256): 1000"
+	if s := call.Format(book); s != expected {
+		t.Errorf("Format() = %q, expected %q", s, expected)
+	}
+
+	if _, err := DecodeCalldata(*erc20Abi, transfer[:3]); !errors.Is(err, ErrInvalidCalldata) {
+		t.Errorf("Expected ErrInvalidCalldata, got %v", err)
+	}
+	if _, err := DecodeCalldata(*erc20Abi, []byte{1, 2, 3, 4}); !errors.Is(err, ErrMethodNotFound) {
+		t.Errorf("Expected ErrMethodNotFound, got %v", err)
+	}
+}
+
+func TestDecodeNestedCalldata(t *testing.T) {
+	erc20Abi, err := erc20.IERC20MetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("GetAbi() failed: %v", err)
+	}
+	decoder := NewCalldataDecoder(*erc20Abi)
+	token := guardUnrelated
+	transfer, _ := erc20Abi.Pack("transfer", guardTrusted, big.NewInt(1000))
+
+	// Multicall3 aggregate3
+	type call3 struct {
+		Target       common.Address
+		AllowFailure bool
+		CallData     []byte
+	}
+	aggregate3, err := nestedCallsABI.Pack("aggregate3", []call3{{token, true, transfer}, {token, false, []byte{1, 2, 3, 4}}})
+	if err != nil {
+		t.Fatalf("Pack(aggregate3) failed: %v", err)
+	}
+	call, err := decoder.Decode(aggregate3)
+	if err != nil {
+		t.Fatalf("Decode(aggregate3) failed: %v", err)
+	}
+	if len(call.Nested) != 2 || call.Nested[0].To != token || !call.Nested[0].AllowFailure ||
+		call.Nested[0].Call == nil || call.Nested[0].Call.Method != "transfer" || call.Nested[1].Call != nil {
+		t.Errorf("Unexpected aggregate3 nested calls: %+v", call.Nested)
+	}
+
+	// Safe execTransaction -> delegatecall MultiSend -> transfer
+	multiSendTx := func(operation byte, to common.Address, value int64, data []byte) []byte {
+		packed := append([]byte{operation}, to.Bytes()...)
+		packed = append(packed, common.BigToHash(big.NewInt(value)).Bytes()...)
+		packed = append(packed, common.BigToHash(big.NewInt(int64(len(data)))).Bytes()...)
+		return append(packed, data...)
+	}
+	transactions := append(multiSendTx(0, token, 0, transfer), multiSendTx(0, guardTrusted, 5, nil)...)
+	multiSend, _ := nestedCallsABI.Pack("multiSend", transactions)
+	multiSendAddress := common.HexToAddress("0x40A2aCCbd92BCA938b02010E17A5b8929b49130D")
+	execTransaction, err := nestedCallsABI.Pack("execTransaction", multiSendAddress, big.NewInt(0), multiSend, uint8(1),
+		big.NewInt(0), big.NewInt(0), big.NewInt(0), common.Address{}, common.Address{}, []byte{})
+	if err != nil {
+		t.Fatalf("Pack(execTransaction) failed: %v", err)
+	}
+	call, err = decoder.Decode(execTransaction)
+	if err != nil {
+		t.Fatalf("Decode(execTransaction) failed: %v", err)
+	}
+	if len(call.Nested) != 1 || call.Nested[0].Operation != 1 || call.Nested[0].Call == nil {
+		t.Fatalf("Unexpected execTransaction nested calls: %+v", call.Nested)
+	}
+	inner := call.Nested[0].Call.Nested
+	if len(inner) != 2 || inner[0].Call.Method != "transfer" || inner[1].To != guardTrusted || inner[1].Value.Int64() != 5 {
+		t.Errorf("Unexpected multiSend calls: %+v", inner)
+	}
+	if s := call.String(); !strings.Contains(s, "(delegatecall)") || !strings.Contains(s, "        transfer(address,uint256)") {
+		t.Errorf("Unexpected String():\n%s", s)
+	}
+
+	// Uniswap 风格的 multicall 调用自身
+	router := common.HexToAddress("0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45")
+	multicall, _ := nestedCallsABI.Pack("multicall0", big.NewInt(1700000000), [][]byte{transfer})
+	tx := types.NewTx(&types.LegacyTx{To: &router, Data: multicall})
+	call, err = decoder.DecodeTx(tx)
+	if err != nil {
+		t.Fatalf("DecodeTx() failed: %v", err)
+	}
+	if call.Signature != "multicall(uint256,bytes[])" || len(call.Nested) != 1 || call.Nested[0].To != router {
+		t.Errorf("Unexpected multicall: %+v", call)
+	}
+}
@@ -0,0 +1,231 @@
+package etherkit
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+//############ Call Options ############
+
+// eth_call 支持的区块标签
+const (
+	BlockTagLatest    = "latest"
+	BlockTagPending   = "pending"
+	BlockTagSafe      = "safe"
+	BlockTagFinalized = "finalized"
+	BlockTagEarliest  = "earliest"
+)
+
+// CallOptions eth_call 的选项，零值等同于在最新区块上调用。
+// BlockNumber、BlockHash、BlockTag 最多只能设置一个
+type CallOptions struct {
+	BlockNumber *big.Int
+	BlockHash   *common.Hash
+	BlockTag    string
+
+	From     common.Address // 零值表示不指定 msg.sender
+	Value    *big.Int
+	Gas      uint64
+	GasPrice *big.Int
+
+	// StateOverrides 调用前临时覆盖的账户状态，不会上链
+	StateOverrides map[common.Address]StateOverride
+	// BlockOverrides 临时覆盖的区块字段
+	BlockOverrides *BlockOverrides
+}
+
+// StateOverride 单个账户的状态覆盖。State 替换全部存储，StateDiff 只覆盖指定的槽位，两者不能同时设置
+type StateOverride struct {
+	Balance   *big.Int
+	Nonce     *uint64
+	Code      []byte
+	State     map[common.Hash]common.Hash
+	StateDiff map[common.Hash]common.Hash
+}
+
+// BlockOverrides eth_call 的区块覆盖，nil 字段保持原值
+type BlockOverrides struct {
+	Number      *big.Int
+	Time        *uint64
+	GasLimit    *uint64
+	Coinbase    *common.Address
+	PrevRandao  *common.Hash
+	BaseFee     *big.Int
+	BlobBaseFee *big.Int
+}
+
+// CallContractWithOptions 按选项调用合约的只读方法，可以指定历史区块、发送者以及状态覆盖
+func (w *Wallet) CallContractWithOptions(ctx context.Context, contractAddress common.Address, contractAbi abi.ABI, functionName string, opts *CallOptions, params ...interface{}) ([]interface{}, error) {
+	inputData, err := BuildContractInputData(contractAbi, functionName, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := w.EthCall(ctx, contractAddress, inputData, opts)
+	if err != nil {
+		return nil, err
+	}
+	return contractAbi.Unpack(functionName, res)
+}
+
+// EthCall 使用原始 calldata 执行 eth_call，opts 可以为 nil
+func (w *Wallet) EthCall(ctx context.Context, to common.Address, data []byte, opts *CallOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &CallOptions{}
+	}
+	block, err := opts.blockParameter()
+	if err != nil {
+		return nil, err
+	}
+
+	args := []interface{}{opts.callArg(to, data), block}
+	if len(opts.StateOverrides) > 0 || opts.BlockOverrides != nil {
+		overrides, err := opts.stateOverrideArg()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, overrides)
+	}
+	if opts.BlockOverrides != nil {
+		args = append(args, opts.BlockOverrides.arg())
+	}
+
+	var res hexutil.Bytes
+	if err := w.GetEthProvider().GetRpcClient().CallContext(ctx, &res, "eth_call", args...); err != nil {
+		// 同时保留 ErrContractCall 和原始错误，调用方可以通过 errors.As 取出 rpc.DataError 中的 revert 数据
+		return nil, fmt.Errorf("eth_call %s: %w: %w", to.Hex(), err, ErrContractCall)
+	}
+	return res, nil
+}
+
+// CallWithOptions 按选项调用只读方法，opts.From 为零值时使用钱包地址
+func (c *BoundContract) CallWithOptions(ctx context.Context, method string, opts *CallOptions, args ...interface{}) ([]interface{}, error) {
+	data, err := c.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	callOpts := CallOptions{}
+	if opts != nil {
+		callOpts = *opts
+	}
+	if callOpts.From == (common.Address{}) {
+		callOpts.From = c.wallet.GetAddress()
+	}
+
+	res, err := c.wallet.EthCall(ctx, c.address, data, &callOpts)
+	if err != nil {
+		return nil, err
+	}
+	return c.abi.Unpack(method, res)
+}
+
+func (o *CallOptions) blockParameter() (interface{}, error) {
+	set := 0
+	for _, ok := range []bool{o.BlockNumber != nil, o.BlockHash != nil, o.BlockTag != ""} {
+		if ok {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, errors.Wrap(ErrInvalidCallOptions, "only one of block number, hash and tag can be set")
+	}
+
+	switch {
+	case o.BlockNumber != nil:
+		if o.BlockNumber.Sign() < 0 {
+			return nil, errors.Wrap(ErrInvalidCallOptions, "negative block number")
+		}
+		return hexutil.EncodeBig(o.BlockNumber), nil
+	case o.BlockHash != nil:
+		// EIP-1898
+		return map[string]interface{}{"blockHash": *o.BlockHash}, nil
+	case o.BlockTag != "":
+		switch o.BlockTag {
+		case BlockTagLatest, BlockTagPending, BlockTagSafe, BlockTagFinalized, BlockTagEarliest:
+			return o.BlockTag, nil
+		}
+		return nil, errors.Wrapf(ErrInvalidCallOptions, "unknown block tag %q", o.BlockTag)
+	default:
+		return BlockTagLatest, nil
+	}
+}
+
+func (o *CallOptions) callArg(to common.Address, data []byte) map[string]interface{} {
+	arg := map[string]interface{}{
+		"to":    to,
+		"input": hexutil.Bytes(data),
+	}
+	if o.From != (common.Address{}) {
+		arg["from"] = o.From
+	}
+	if o.Value != nil {
+		arg["value"] = (*hexutil.Big)(o.Value)
+	}
+	if o.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(o.Gas)
+	}
+	if o.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(o.GasPrice)
+	}
+	return arg
+}
+
+func (o *CallOptions) stateOverrideArg() (map[common.Address]map[string]interface{}, error) {
+	overrides := make(map[common.Address]map[string]interface{}, len(o.StateOverrides))
+	for address, override := range o.StateOverrides {
+		if override.State != nil && override.StateDiff != nil {
+			return nil, errors.Wrapf(ErrInvalidCallOptions, "both state and stateDiff set for %s", address.Hex())
+		}
+		account := map[string]interface{}{}
+		if override.Balance != nil {
+			account["balance"] = (*hexutil.Big)(override.Balance)
+		}
+		if override.Nonce != nil {
+			account["nonce"] = hexutil.Uint64(*override.Nonce)
+		}
+		if override.Code != nil {
+			account["code"] = hexutil.Bytes(override.Code)
+		}
+		if override.State != nil {
+			account["state"] = override.State
+		}
+		if override.StateDiff != nil {
+			account["stateDiff"] = override.StateDiff
+		}
+		overrides[address] = account
+	}
+	return overrides, nil
+}
+
+func (b *BlockOverrides) arg() map[string]interface{} {
+	arg := map[string]interface{}{}
+	if b.Number != nil {
+		arg["number"] = (*hexutil.Big)(b.Number)
+	}
+	if b.Time != nil {
+		arg["time"] = hexutil.Uint64(*b.Time)
+	}
+	if b.GasLimit != nil {
+		arg["gasLimit"] = hexutil.Uint64(*b.GasLimit)
+	}
+	if b.Coinbase != nil {
+		arg["feeRecipient"] = *b.Coinbase
+	}
+	if b.PrevRandao != nil {
+		arg["prevRandao"] = *b.PrevRandao
+	}
+	if b.BaseFee != nil {
+		arg["baseFeePerGas"] = (*hexutil.Big)(b.BaseFee)
+	}
+	if b.BlobBaseFee != nil {
+		arg["blobBaseFee"] = (*hexutil.Big)(b.BlobBaseFee)
+	}
+	return arg
+}
@@ -0,0 +1,142 @@
+package etherkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// callOptsTestService 记录 eth_call 的参数并返回固定结果
+type callOptsTestService struct {
+	args           map[string]interface{}
+	block          json.RawMessage
+	overrides      map[string]map[string]interface{}
+	blockOverrides map[string]interface{}
+	err            error
+}
+
+// callOptsTestRevertError 带 revert 数据的 JSON-RPC 错误，客户端收到后为 rpc.DataError
+type callOptsTestRevertError struct{}
+
+func (callOptsTestRevertError) Error() string          { return "execution reverted" }
+func (callOptsTestRevertError) ErrorCode() int         { return 3 }
+func (callOptsTestRevertError) ErrorData() interface{} { return "0x08c379a0" }
+
+func (s *callOptsTestService) Call(args map[string]interface{}, block json.RawMessage, overrides *map[string]map[string]interface{}, blockOverrides *map[string]interface{}) (hexutil.Bytes, error) {
+	s.args, s.block, s.overrides, s.blockOverrides = args, block, nil, nil
+	if overrides != nil {
+		s.overrides = *overrides
+	}
+	if blockOverrides != nil {
+		s.blockOverrides = *blockOverrides
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return common.BigToHash(big.NewInt(42)).Bytes(), nil
+}
+
+func newCallOptsTestWallet(t *testing.T) (*Wallet, *callOptsTestService) {
+	service := &callOptsTestService{}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatalf("RegisterName() failed: %v", err)
+	}
+	client := rpc.DialInProc(server)
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+
+	wallet, err := NewWalletWithComponents(nil, &Provider{rc: client, ec: ethclient.NewClient(client)})
+	if err != nil {
+		t.Fatalf("NewWalletWithComponents() failed: %v", err)
+	}
+	return wallet, service
+}
+
+func TestCallContractWithOptions(t *testing.T) {
+	wallet, service := newCallOptsTestWallet(t)
+	contractAbi, err := GetABI(typedCallTestABI)
+	if err != nil {
+		t.Fatalf("GetABI() failed: %v", err)
+	}
+	token := guardUnrelated
+
+	// 默认在 latest 上调用，不带 from 和覆盖
+	res, err := wallet.CallContractWithOptions(context.Background(), token, contractAbi, "totalSupply", nil)
+	if err != nil || res[0].(*big.Int).Int64() != 42 {
+		t.Fatalf("CallContractWithOptions() = %v, %v", res, err)
+	}
+	if string(service.block) != `"latest"` || service.args["from"] != nil || service.overrides != nil {
+		t.Errorf("Unexpected default call: %v %s %v", service.args, service.block, service.overrides)
+	}
+
+	nonce := uint64(7)
+	timestamp := uint64(1700000000)
+	opts := &CallOptions{
+		BlockNumber: big.NewInt(19000000),
+		From:        guardTrusted,
+		Value:       BigInt1,
+		Gas:         100000,
+		StateOverrides: map[common.Address]StateOverride{
+			guardTrusted: {Balance: BigInt100, Nonce: &nonce},
+			token:        {Code: []byte{0x60, 0x00}, StateDiff: map[common.Hash]common.Hash{common.HexToHash("0x01"): common.HexToHash("0x02")}},
+		},
+		BlockOverrides: &BlockOverrides{Time: &timestamp},
+	}
+	if _, err := wallet.CallContractWithOptions(context.Background(), token, contractAbi, "totalSupply", opts); err != nil {
+		t.Fatalf("CallContractWithOptions() failed: %v", err)
+	}
+	if string(service.block) != `"0x121eac0"` || service.args["from"] != hexutil.Encode(guardTrusted.Bytes()) ||
+		service.args["value"] != "0x1" || service.args["gas"] != "0x186a0" {
+		t.Errorf("Unexpected call arguments: %v %s", service.args, service.block)
+	}
+	account := service.overrides[hexutil.Encode(guardTrusted.Bytes())]
+	if account["balance"] != "0x64" || account["nonce"] != "0x7" {
+		t.Errorf("Unexpected account override: %v", account)
+	}
+	tokenOverride := service.overrides[hexutil.Encode(token.Bytes())]
+	if tokenOverride["code"] != "0x6000" || tokenOverride["stateDiff"] == nil || tokenOverride["state"] != nil {
+		t.Errorf("Unexpected token override: %v", tokenOverride)
+	}
+	if service.blockOverrides["time"] != "0x6553f100" {
+		t.Errorf("Unexpected block overrides: %v", service.blockOverrides)
+	}
+
+	// 按区块哈希调用（EIP-1898）
+	hash := common.HexToHash("0xabc")
+	if _, err := wallet.EthCall(context.Background(), token, nil, &CallOptions{BlockHash: &hash}); err != nil {
+		t.Fatalf("EthCall() failed: %v", err)
+	}
+	if string(service.block) != `{"blockHash":"`+hash.Hex()+`"}` {
+		t.Errorf("Unexpected block parameter: %s", service.block)
+	}
+
+	// revert 数据通过 rpc.DataError 保留
+	service.err = callOptsTestRevertError{}
+	_, err = wallet.EthCall(context.Background(), token, nil, nil)
+	var dataErr rpc.DataError
+	if !errors.Is(err, ErrContractCall) || !errors.As(err, &dataErr) || dataErr.ErrorData() != "0x08c379a0" {
+		t.Errorf("Expected ErrContractCall with revert data, got %v", err)
+	}
+	service.err = nil
+
+	invalid := []*CallOptions{
+		{BlockNumber: big.NewInt(1), BlockTag: BlockTagSafe},
+		{BlockTag: "newest"},
+		{StateOverrides: map[common.Address]StateOverride{token: {State: map[common.Hash]common.Hash{}, StateDiff: map[common.Hash]common.Hash{}}}},
+	}
+	for _, opts := range invalid {
+		if _, err := wallet.EthCall(context.Background(), token, nil, opts); !errors.Is(err, ErrInvalidCallOptions) {
+			t.Errorf("Expected ErrInvalidCallOptions for %+v, got %v", opts, err)
+		}
+	}
+}
@@ -4,15 +4,17 @@ import "math/big"
 
 // 网络链ID常量
 const (
-	MainnetChainID   = 1
-	GoerliChainID    = 5
-	SepoliaChainID   = 11155111
-	PolygonChainID   = 137
-	BSCChainID       = 56
-	ArbitrumChainID  = 42161
-	OptimismChainID  = 10
-	AvalancheChainID = 43114
-	FantomChainID    = 250
+	MainnetChainID    = 1
+	GoerliChainID     = 5
+	SepoliaChainID    = 11155111
+	PolygonChainID    = 137
+	BSCChainID        = 56
+	ArbitrumChainID   = 42161
+	OptimismChainID   = 10
+	AvalancheChainID  = 43114
+	FantomChainID     = 250
+	RSKMainnetChainID = 30
+	RSKTestnetChainID = 31
 )
 
 // Gas 相关常量
@@ -4,8 +4,10 @@ import (
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
 )
 
 //############ Contract ############
@@ -17,18 +19,152 @@ func GetABI(abiStr string) (abi.ABI, error) {
 }
 
 // GetContractMethodId 获得合约的methodId
-// 参数method，如：transfer(address,uint256)
+// 参数method，如：transfer(address,uint256)。能够解析时先规范化签名（见 CanonicalSignature），
+// 否则直接对字符串求哈希；需要校验输入时使用 ComputeMethodId
 func GetContractMethodId(method string) string {
+	if sig, err := canonicalSignature(method, methodSignature); err == nil {
+		method = sig
+	}
 	methodId := hexutil.Encode(crypto.Keccak256([]byte(method))[:4])
 	return methodId
 }
 
-// GetEventTopic 获得事件的topic。event字符串如：transfer(address,uint256)
+// GetEventTopic 获得事件的topic。event字符串如：Transfer(address,address,uint256)
+// 能够解析时先规范化签名，否则直接对字符串求哈希；需要校验输入时使用 ComputeEventTopic
 func GetEventTopic(event string) string {
+	if sig, err := canonicalSignature(event, eventSignature); err == nil {
+		event = sig
+	}
 	return crypto.Keccak256Hash([]byte(event)).String()
 }
 
+// ComputeMethodId 规范化签名后计算4字节的methodId。签名无效、是事件签名（event 关键字）
+// 或参数带 indexed 时返回 ErrInvalidTextSignature
+func ComputeMethodId(signature string) (string, error) {
+	sig, err := canonicalSignature(signature, methodSignature)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(crypto.Keccak256([]byte(sig))[:4]), nil
+}
+
+// ComputeEventTopic 规范化签名后计算事件的topic0。没有关键字的签名按事件解析；
+// 签名无效或是方法、错误签名（function/error 关键字）时返回 ErrInvalidTextSignature
+func ComputeEventTopic(signature string) (common.Hash, error) {
+	sig, err := canonicalSignature(signature, eventSignature)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash([]byte(sig)), nil
+}
+
+// CanonicalSignature 将方法、事件或错误的签名规范化为用于计算哈希的形式：
+// 去除空白、参数名、indexed 等修饰符以及 function/event/error 关键字和返回值，
+// uint/int 扩展为 uint256/int256，tuple(...) 写作 (...)。
+// 例如 "function transfer(address to, uint amount) returns (bool)" 规范化为 "transfer(address,uint256)"
+func CanonicalSignature(signature string) (string, error) {
+	return canonicalSignature(signature, anySignature)
+}
+
+// signatureKind 解析签名时接受的类型
+type signatureKind int
+
+const (
+	anySignature    signatureKind = iota // 方法、事件或错误
+	methodSignature                      // 方法或错误，拒绝事件
+	eventSignature                       // 只接受事件
+)
+
+// canonicalSignature 按 kind 规范化签名
+func canonicalSignature(signature string, kind signatureKind) (string, error) {
+	name, inputs, err := parseSignatureFragment(signature, kind)
+	if err != nil {
+		return "", err
+	}
+	types := make([]string, len(inputs))
+	for i, input := range inputs {
+		types[i] = canonicalArgumentType(input)
+	}
+	return name + "(" + strings.Join(types, ",") + ")", nil
+}
+
+// parseSignatureFragment 解析签名的名字和参数。没有关键字时，anySignature 先按方法解析、
+// 失败后按事件解析（允许 indexed），eventSignature 直接按事件解析；
+// methodSignature 拒绝事件签名，eventSignature 拒绝方法和错误签名
+func parseSignatureFragment(signature string, kind signatureKind) (string, []abi.ArgumentMarshaling, error) {
+	fragment := strings.TrimSpace(signature)
+	keyword := false
+	if i := strings.IndexAny(fragment, " \t("); i > 0 {
+		switch fragment[:i] {
+		case "function", "event", "error", "constructor", "fallback", "receive":
+			keyword = true
+		}
+	}
+
+	var entry humanABIEntry
+	var err error
+	if kind == eventSignature && !keyword {
+		entry, err = parseHumanABIFragment("event " + fragment)
+	} else {
+		entry, err = parseHumanABIFragment(fragment)
+		if err != nil && kind == anySignature && !keyword {
+			entry, err = parseHumanABIFragment("event " + fragment)
+		}
+	}
+	if err == nil && kind == methodSignature && entry.Type == "event" {
+		err = errors.New("event signature is not a method")
+	}
+	if err == nil && kind == eventSignature && entry.Type != "event" {
+		err = errors.Errorf("%s signature is not an event", entry.Type)
+	}
+	if err == nil && entry.Name == "" {
+		err = errors.Errorf("%s has no signature", entry.Type)
+	}
+	if err == nil {
+		// 校验 tuple 等复合类型
+		for _, input := range entry.Inputs {
+			if _, err = abi.NewType(input.Type, "", input.Components); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return "", nil, errors.Wrapf(ErrInvalidTextSignature, "%q: %v", signature, err)
+	}
+	return entry.Name, entry.Inputs, nil
+}
+
+// canonicalArgumentType 参数的规范类型，tuple 展开为 (type1,type2)
+func canonicalArgumentType(arg abi.ArgumentMarshaling) string {
+	if !strings.HasPrefix(arg.Type, "tuple") {
+		return arg.Type
+	}
+	components := make([]string, len(arg.Components))
+	for i, component := range arg.Components {
+		components[i] = canonicalArgumentType(component)
+	}
+	return "(" + strings.Join(components, ",") + ")" + strings.TrimPrefix(arg.Type, "tuple")
+}
+
 // BuildContractInputData 构建合约的input data
 func BuildContractInputData(contract abi.ABI, name string, args ...interface{}) ([]byte, error) {
 	return contract.Pack(name, args...)
 }
+
+//############ Contract Address ############
+
+// ComputeContractAddress 计算 deployer 以 nonce 通过 CREATE 部署的合约地址，即 keccak256(rlp([deployer, nonce]))[12:]
+func ComputeContractAddress(deployer common.Address, nonce uint64) common.Address {
+	return crypto.CreateAddress(deployer, nonce)
+}
+
+// ComputeCreate2Address 计算 deployer 以 salt 和 initCode 通过 CREATE2 部署的合约地址（EIP-1014）
+func ComputeCreate2Address(deployer common.Address, salt [32]byte, initCode []byte) common.Address {
+	return ComputeCreate2AddressFromHash(deployer, salt, crypto.Keccak256Hash(initCode))
+}
+
+// ComputeCreate2AddressFromHash 与 ComputeCreate2Address 相同，但直接使用 keccak256(initCode)，
+// 即 keccak256(0xff ‖ deployer ‖ salt ‖ initCodeHash)[12:]
+func ComputeCreate2AddressFromHash(deployer common.Address, salt [32]byte, initCodeHash common.Hash) common.Address {
+	return crypto.CreateAddress2(deployer, salt, initCodeHash.Bytes())
+}
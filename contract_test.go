@@ -1,11 +1,13 @@
 package etherkit
 
 import (
+	"errors"
 	"math/big"
 	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 func TestGetABI(t *testing.T) {
@@ -124,6 +126,60 @@ func TestGetContractMethodId(t *testing.T) {
 	}
 }
 
+func TestCanonicalSignature(t *testing.T) {
+	tests := []struct {
+		signature string
+		expected  string
+	}{
+		{"transfer(address,uint256)", "transfer(address,uint256)"},
+		{"transfer(address, uint256)", "transfer(address,uint256)"},
+		{"transfer(address,uint)", "transfer(address,uint256)"},
+		{"function transfer(address to, uint amount) external returns (bool)", "transfer(address,uint256)"},
+		{"event Transfer(address indexed from, address indexed to, uint256 value)", "Transfer(address,address,uint256)"},
+		{"Transfer(address indexed from, address indexed to, uint256 value)", "Transfer(address,address,uint256)"},
+		{"error InsufficientBalance(uint available, int required)", "InsufficientBalance(uint256,int256)"},
+		{"aggregate3(tuple(address target, bool allowFailure, bytes callData)[] calls)", "aggregate3((address,bool,bytes)[])"},
+		{"swap( (address, uint[2])[3] , byte b)", "swap((address,uint256[2])[3],bytes1)"},
+		{"test()", "test()"},
+	}
+	for _, tt := range tests {
+		result, err := CanonicalSignature(tt.signature)
+		if err != nil || result != tt.expected {
+			t.Errorf("CanonicalSignature(%q) = %q, %v, expected %q", tt.signature, result, err, tt.expected)
+		}
+	}
+
+	for _, invalid := range []string{"", "transfer", "transfer(address,uint256", "transfer(address,,uint256)", "transfer(uint7)", "1transfer()", "constructor(address)", "(address)"} {
+		if _, err := CanonicalSignature(invalid); !errors.Is(err, ErrInvalidTextSignature) {
+			t.Errorf("Expected ErrInvalidTextSignature for %q, got %v", invalid, err)
+		}
+	}
+
+	// 规范化后的选择器与 topic
+	if id, err := ComputeMethodId("function transfer(address to, uint amount)"); err != nil || id != ERC20TransferMethodID {
+		t.Errorf("ComputeMethodId() = %s, %v", id, err)
+	}
+	if topic, err := ComputeEventTopic("event Transfer(address indexed from, address indexed to, uint value)"); err != nil || topic.Hex() != ERC20TransferEventTopic {
+		t.Errorf("ComputeEventTopic() = %s, %v", topic.Hex(), err)
+	}
+	for _, invalid := range []string{"transfer(address", "transfer(address indexed to)", "event Transfer(address,address,uint256)"} {
+		if _, err := ComputeMethodId(invalid); !errors.Is(err, ErrInvalidTextSignature) {
+			t.Errorf("ComputeMethodId(%q) expected ErrInvalidTextSignature, got %v", invalid, err)
+		}
+	}
+	if topic, err := ComputeEventTopic("Transfer(address indexed from, address indexed to, uint value)"); err != nil || topic.Hex() != ERC20TransferEventTopic {
+		t.Errorf("ComputeEventTopic() without keyword = %s, %v", topic.Hex(), err)
+	}
+	for _, invalid := range []string{"function transfer(address,uint256)", "error InsufficientBalance(uint256)", "Transfer(address", "constructor(address)"} {
+		if _, err := ComputeEventTopic(invalid); !errors.Is(err, ErrInvalidTextSignature) {
+			t.Errorf("ComputeEventTopic(%q) expected ErrInvalidTextSignature, got %v", invalid, err)
+		}
+	}
+	if id := GetContractMethodId("transfer(address, uint)"); id != ERC20TransferMethodID {
+		t.Errorf("GetContractMethodId() = %s", id)
+	}
+}
+
 func TestGetEventTopic(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -262,6 +318,48 @@ func TestBuildContractInputData(t *testing.T) {
 	}
 }
 
+func TestComputeContractAddress(t *testing.T) {
+	deployer := common.HexToAddress("0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0")
+	expected := []string{
+		"0xcd234a471b72ba2f1ccf0a70fcaba648a5eecd8d",
+		"0x343c43a37d37dff08ae8c4a11544c718abb4fcf8",
+		"0xf778b86fa74e846c4f0a1fbd1335fe81c00a0c91",
+	}
+	for nonce, address := range expected {
+		if got := ComputeContractAddress(deployer, uint64(nonce)); got != common.HexToAddress(address) {
+			t.Errorf("ComputeContractAddress(%d) = %s, expected %s", nonce, got.Hex(), address)
+		}
+	}
+}
+
+func TestComputeCreate2Address(t *testing.T) {
+	// EIP-1014 测试向量
+	tests := []struct {
+		deployer string
+		salt     string
+		initCode string
+		expected string
+	}{
+		{"0x0000000000000000000000000000000000000000", "0x00", "0x00", "0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38"},
+		{"0xdeadbeef00000000000000000000000000000000", "0x00", "0x00", "0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3"},
+		{"0x00000000000000000000000000000000deadbeef", "0xcafebabe", "0xdeadbeef", "0x60f3f640a8508fC6a86d45DF051962668E1e8AC7"},
+		{"0x0000000000000000000000000000000000000000", "0x00", "0x", "0xE33C0C7F7df4809055C3ebA6c09CFe4BaF1BD9e0"},
+	}
+
+	for _, tt := range tests {
+		deployer := common.HexToAddress(tt.deployer)
+		salt := common.HexToHash(tt.salt)
+		initCode := common.FromHex(tt.initCode)
+
+		if got := ComputeCreate2Address(deployer, salt, initCode); got.Hex() != tt.expected {
+			t.Errorf("ComputeCreate2Address() = %s, expected %s", got.Hex(), tt.expected)
+		}
+		if got := ComputeCreate2AddressFromHash(deployer, salt, crypto.Keccak256Hash(initCode)); got.Hex() != tt.expected {
+			t.Errorf("ComputeCreate2AddressFromHash() = %s, expected %s", got.Hex(), tt.expected)
+		}
+	}
+}
+
 // 辅助函数：获取方法签名
 func getMethodSignature(abi interface{}, methodName string) string {
 	// 这里简化处理，实际项目中可以从ABI中提取完整签名
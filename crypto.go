@@ -2,14 +2,13 @@ package etherkit
 
 import (
 	"crypto/ecdsa"
-	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
-	hdwallet "github.com/miguelmota/go-ethereum-hdwallet"
 	"github.com/pkg/errors"
 )
 
@@ -20,11 +19,36 @@ func GeneratePrivateKey() (*ecdsa.PrivateKey, error) {
 	return crypto.GenerateKey()
 }
 
-// GetHexPrivateKey 创建十六进制的私钥(不以0x开头)
+// GetHexPrivateKey 创建十六进制的私钥(不以0x开头)。
+// 返回的字符串无法被清零，会一直留在内存中，长期运行的进程应优先使用 GetPrivateKeyBytes 并在使用后调用 ZeroBytes
 func GetHexPrivateKey(privateKey *ecdsa.PrivateKey) string {
 	return hexutil.Encode(crypto.FromECDSA(privateKey))[2:]
 }
 
+// GetPrivateKeyBytes 获得32字节的私钥，调用方使用完后应调用 ZeroBytes 清零
+func GetPrivateKeyBytes(privateKey *ecdsa.PrivateKey) []byte {
+	return crypto.FromECDSA(privateKey)
+}
+
+// ZeroBytes 将字节切片清零
+func ZeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// ZeroPrivateKey 将私钥的标量 D 清零，清零后的私钥不能再使用
+func ZeroPrivateKey(privateKey *ecdsa.PrivateKey) {
+	if privateKey == nil || privateKey.D == nil {
+		return
+	}
+	words := privateKey.D.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	privateKey.D.SetInt64(0)
+}
+
 // PrivateKeyToAddress 从私钥中获得地址
 func PrivateKeyToAddress(privateKey *ecdsa.PrivateKey) common.Address {
 	publicKey := privateKey.Public()
@@ -53,44 +77,140 @@ func BuildPrivateKeyFromHex(privateKeyHex string) (*ecdsa.PrivateKey, error) {
 	return privateKey, nil
 }
 
+// BuildPrivateKeyFromMnemonic 从助记词获得第0个账户的私钥
 func BuildPrivateKeyFromMnemonic(mnemonic string) (*ecdsa.PrivateKey, error) {
 	return BuildPrivateKeyFromMnemonicAndAccountId(mnemonic, 0)
 }
 
 // BuildPrivateKeyFromMnemonicAndAccountId 从助记词获得私钥
 func BuildPrivateKeyFromMnemonicAndAccountId(mnemonic string, accountId uint32) (*ecdsa.PrivateKey, error) {
-	wallet, err := hdwallet.NewFromMnemonic(mnemonic)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create HD wallet from mnemonic")
-	}
-	path, err := accounts.ParseDerivationPath(fmt.Sprintf("m/44'/60'/0'/0/%d", accountId))
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to parse derivation path")
-	}
-	account, err := wallet.Derive(path, true)
+	return BuildPrivateKeyFromMnemonicWithPassphrase(mnemonic, "", accountId)
+}
+
+// BuildPrivateKeyFromMnemonicWithPassphrase 使用助记词和 BIP39 passphrase 获得私钥，支持所有 BIP39 词表
+func BuildPrivateKeyFromMnemonicWithPassphrase(mnemonic, passphrase string, accountId uint32) (*ecdsa.PrivateKey, error) {
+	path, err := DerivationPathForIndex(DefaultDerivationPath, accountId)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to derive account from HD wallet")
+		return nil, err
 	}
-	pk, err := wallet.PrivateKey(account)
+	return BuildPrivateKeyFromMnemonicAndPath(mnemonic, passphrase, path)
+}
+
+// HashPersonalMessage 计算 EIP-191 personal_sign 的摘要：keccak256("\x19Ethereum Signed Message:\n" + len(message) + message)
+func HashPersonalMessage(message []byte) common.Hash {
+	return common.BytesToHash(accounts.TextHash(message))
+}
+
+// SignPersonalMessage 使用私钥对 EIP-191 personal_sign 消息签名，返回65字节签名（V为27/28）
+func SignPersonalMessage(privateKey *ecdsa.PrivateKey, message []byte) ([]byte, error) {
+	signature, err := crypto.Sign(HashPersonalMessage(message).Bytes(), privateKey)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get account's private key from HD wallet")
+		return nil, errors.Wrap(ErrSignatureFailed, err.Error())
 	}
-	return pk, nil
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
+
+// RecoverPersonalMessageSigner 从 EIP-191 personal_sign 签名中恢复签名者地址
+func RecoverPersonalMessageSigner(message, signature []byte) (common.Address, error) {
+	return RecoverAddress(HashPersonalMessage(message).Bytes(), signature)
 }
 
 // VerifySignature 验证签名
-// address: 用于签名的地址
+// address: 用于签名的地址（不区分大小写）
 // digestHash: 用于验证的原始数据
 // signature: 需要进行验证的签名数据
 func VerifySignature(address string, data, signature []byte) bool {
+	_, err := VerifySignatureAddress(address, data, signature)
+	return err == nil
+}
+
+// VerifySignatureAddress 验证对 keccak256(data) 的签名，返回恢复出的签名者地址。
+// address 可以是 common.Address 或任意大小写的十六进制字符串。
+// 签名格式错误时返回 ErrInvalidSignature，签名者与 address 不一致时返回 ErrSignatureVerificationFailed。
+func VerifySignatureAddress(address interface{}, data, signature []byte) (common.Address, error) {
+	return VerifyHashSignature(address, crypto.Keccak256(data), signature)
+}
+
+// VerifyHashSignature 验证对摘要 hash 的签名，返回恢复出的签名者地址
+func VerifyHashSignature(address interface{}, hash, signature []byte) (common.Address, error) {
+	expected, err := toAddress(address)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	recovered, err := RecoverAddress(hash, signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if recovered != expected {
+		return recovered, errors.Wrapf(ErrSignatureVerificationFailed, "recovered %s, expected %s", recovered.Hex(), expected.Hex())
+	}
+	return recovered, nil
+}
 
-	digestHash := crypto.Keccak256Hash(data)
-	//returns the public key that created the given signature.
-	sigPublicKeyECDSA, err := crypto.SigToPub(digestHash.Bytes(), signature)
+// RecoverSignerAddress 从 keccak256(data) 的签名中恢复签名者地址，与 Wallet.Signature 对应
+func RecoverSignerAddress(data, signature []byte) (common.Address, error) {
+	return RecoverAddress(crypto.Keccak256(data), signature)
+}
+
+// RecoverAddress 从32字节摘要和签名中恢复签名者地址，签名会先经过 NormalizeSignature 规范化
+func RecoverAddress(hash, signature []byte) (common.Address, error) {
+	publicKey, err := RecoverPublicKey(hash, signature)
 	if err != nil {
-		return false
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
+
+// NormalizeSignature 将签名规范化为65字节的 [R || S || V] 格式，V 为 0 或 1。
+// 支持的输入：
+//   - 65字节签名，V 为 0/1、27/28 或 EIP-155 形式（chainId*2+35/36）
+//   - 64字节 EIP-2098 紧凑签名 [R || yParityAndS]
+//
+// R、S 必须在曲线阶范围内，且 S 必须处于低半区（EIP-2），否则返回 ErrInvalidSignature。
+func NormalizeSignature(signature []byte) ([]byte, error) {
+	sig := make([]byte, crypto.SignatureLength)
+
+	switch len(signature) {
+	case crypto.SignatureLength:
+		copy(sig, signature)
+		v := sig[crypto.RecoveryIDOffset]
+		switch {
+		case v == 0 || v == 1:
+		case v == 27 || v == 28:
+			v -= 27
+		case v >= 35:
+			v = (v - 35) % 2
+		default:
+			return nil, errors.Wrapf(ErrInvalidSignature, "invalid recovery id %d", v)
+		}
+		sig[crypto.RecoveryIDOffset] = v
+	case crypto.SignatureLength - 1:
+		// EIP-2098: yParity 存放在 S 的最高位
+		copy(sig, signature)
+		sig[crypto.RecoveryIDOffset] = sig[32] >> 7
+		sig[32] &= 0x7f
+	default:
+		return nil, errors.Wrapf(ErrInvalidSignature, "signature length %d", len(signature))
 	}
 
-	sigAddress := crypto.PubkeyToAddress(*sigPublicKeyECDSA)
-	return sigAddress.String() == address
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	if !crypto.ValidateSignatureValues(sig[crypto.RecoveryIDOffset], r, s, true) {
+		return nil, errors.Wrap(ErrInvalidSignature, "signature values out of range or s is not low")
+	}
+	return sig, nil
+}
+
+// CompactSignature 将签名转换为64字节的 EIP-2098 紧凑格式
+func CompactSignature(signature []byte) ([]byte, error) {
+	sig, err := NormalizeSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	compact := make([]byte, crypto.SignatureLength-1)
+	copy(compact, sig[:64])
+	compact[32] |= sig[crypto.RecoveryIDOffset] << 7
+	return compact, nil
 }
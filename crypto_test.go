@@ -1,6 +1,8 @@
 package etherkit
 
 import (
+	"errors"
+	"math/big"
 	"strings"
 	"testing"
 
@@ -237,6 +239,135 @@ func TestVerifySignature(t *testing.T) {
 	}
 }
 
+func TestVerifySignatureAddress(t *testing.T) {
+	pk, err := GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("GeneratePrivateKey() failed: %v", err)
+	}
+	address := PrivateKeyToAddress(pk)
+	testData := []byte("Hello, Ethereum!")
+
+	signature, err := crypto.Sign(crypto.Keccak256(testData), pk)
+	if err != nil {
+		t.Fatalf("crypto.Sign() failed: %v", err)
+	}
+
+	// 不同大小写和类型的地址都可以通过验证
+	for _, addr := range []interface{}{address, &address, address.Hex(), strings.ToLower(address.Hex()), strings.ToUpper(address.Hex()[2:])} {
+		recovered, err := VerifySignatureAddress(addr, testData, signature)
+		if err != nil {
+			t.Errorf("VerifySignatureAddress(%v) failed: %v", addr, err)
+		}
+		if recovered != address {
+			t.Errorf("recovered = %s, expected %s", recovered.Hex(), address.Hex())
+		}
+	}
+	if !VerifySignature(strings.ToLower(address.Hex()), testData, signature) {
+		t.Error("Lower-case address signature verification failed")
+	}
+
+	// 签名者不一致
+	recovered, err := VerifySignatureAddress("0x0000000000000000000000000000000000000001", testData, signature)
+	if !errors.Is(err, ErrSignatureVerificationFailed) {
+		t.Errorf("Expected ErrSignatureVerificationFailed, got %v", err)
+	}
+	if recovered != address {
+		t.Errorf("recovered = %s, expected %s", recovered.Hex(), address.Hex())
+	}
+
+	// 地址格式错误
+	if _, err := VerifySignatureAddress("0x1234", testData, signature); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("Expected ErrInvalidAddress, got %v", err)
+	}
+
+	// 签名格式错误
+	if _, err := VerifySignatureAddress(address, testData, signature[:30]); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestNormalizeSignature(t *testing.T) {
+	pk, err := GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("GeneratePrivateKey() failed: %v", err)
+	}
+	address := PrivateKeyToAddress(pk)
+	hash := crypto.Keccak256([]byte("normalize"))
+
+	signature, err := crypto.Sign(hash, pk)
+	if err != nil {
+		t.Fatalf("crypto.Sign() failed: %v", err)
+	}
+	recID := signature[64]
+
+	withV := func(v byte) []byte {
+		sig := append([]byte{}, signature...)
+		sig[64] = v
+		return sig
+	}
+	compact, err := CompactSignature(signature)
+	if err != nil {
+		t.Fatalf("CompactSignature() failed: %v", err)
+	}
+	if len(compact) != 64 {
+		t.Fatalf("compact signature length = %d, expected 64", len(compact))
+	}
+
+	tests := []struct {
+		name      string
+		signature []byte
+	}{
+		{"V 0/1", signature},
+		{"V 27/28", withV(recID + 27)},
+		{"EIP-155 V", withV(recID + 37)}, // chainId = 1
+		{"EIP-2098 compact", compact},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, err := NormalizeSignature(tt.signature)
+			if err != nil {
+				t.Fatalf("NormalizeSignature() failed: %v", err)
+			}
+			if string(normalized) != string(signature) {
+				t.Errorf("normalized = %x, expected %x", normalized, signature)
+			}
+			recovered, err := RecoverAddress(hash, tt.signature)
+			if err != nil {
+				t.Fatalf("RecoverAddress() failed: %v", err)
+			}
+			if recovered != address {
+				t.Errorf("recovered = %s, expected %s", recovered.Hex(), address.Hex())
+			}
+		})
+	}
+
+	// 高 S 值签名（S' = N - S）必须被拒绝
+	highS := withV(recID ^ 1)
+	s := new(big.Int).SetBytes(signature[32:64])
+	s.Sub(crypto.S256().Params().N, s)
+	s.FillBytes(highS[32:64])
+
+	invalid := []struct {
+		name      string
+		signature []byte
+	}{
+		{"High S", highS},
+		{"Invalid V", withV(5)},
+		{"Zero R", append(make([]byte, 32), signature[32:]...)},
+		{"Wrong length", signature[:63]},
+		{"Empty", nil},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NormalizeSignature(tt.signature); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Expected ErrInvalidSignature, got %v", err)
+			}
+		})
+	}
+}
+
 // 性能测试
 func BenchmarkGeneratePrivateKey(b *testing.B) {
 	b.ResetTimer()
@@ -0,0 +1,212 @@
+package etherkit
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+//############ Contract Deployment ############
+
+// DeterministicDeployerAddress Arachnid 的确定性部署合约，绝大多数 EVM 链上地址相同。
+// 调用数据为 salt(32字节) ‖ initCode，使用 CREATE2 部署
+const DeterministicDeployerAddress = "0x4e59b44847b379578588920cA78FbF26c0B4956C"
+
+// libraryPlaceholderRegexp 匹配 solc 的库占位符：新格式 __$<34位hex>$__ 和旧格式 __<库名>__（共40个字符）
+var libraryPlaceholderRegexp = regexp.MustCompile(`__\$[0-9a-fA-F]{34}\$__|__[^$]{36}__`)
+
+// DeployOptions 部署合约的选项，零值表示使用 CREATE 部署并自动计算 nonce、gas
+type DeployOptions struct {
+	Value    *big.Int // 随部署发送的本位币
+	Nonce    uint64   // 0表示使用 pending nonce
+	GasLimit uint64   // 0表示自动估算
+	GasPrice *big.Int // nil表示使用建议的 gas price
+
+	// Libraries 链接的库地址，key 为完全限定名（如 contracts/Math.sol:Math）或旧格式的库名
+	Libraries map[string]common.Address
+
+	// Salt 不为 nil 时通过 Factory 使用 CREATE2 部署
+	Salt *common.Hash
+	// Factory CREATE2 部署合约，零值表示 DeterministicDeployerAddress
+	Factory common.Address
+}
+
+// DeployResult 部署结果
+type DeployResult struct {
+	Address  common.Address
+	Tx       *types.Transaction
+	Receipt  *types.Receipt
+	Contract *BoundContract
+}
+
+// LinkBytecode 将十六进制字节码中的库占位符替换为库地址，仍有未链接的占位符时返回 ErrUnlinkedLibrary
+func LinkBytecode(bytecode string, libraries map[string]common.Address) (string, error) {
+	linked := strings.TrimPrefix(strings.TrimSpace(bytecode), "0x")
+	for name, address := range libraries {
+		replacement := hex.EncodeToString(address.Bytes())
+		for _, placeholder := range libraryPlaceholders(name) {
+			linked = strings.ReplaceAll(linked, placeholder, replacement)
+		}
+	}
+
+	if placeholders := libraryPlaceholderRegexp.FindAllString(linked, -1); len(placeholders) > 0 {
+		return "", errors.Wrapf(ErrUnlinkedLibrary, "%s", strings.Join(uniqueStrings(placeholders), ", "))
+	}
+	return "0x" + linked, nil
+}
+
+// BuildDeployData 链接库并拼接构造函数参数，得到合约的 init code
+func BuildDeployData(contractAbi abi.ABI, bytecode string, libraries map[string]common.Address, args ...interface{}) ([]byte, error) {
+	linked, err := LinkBytecode(bytecode, libraries)
+	if err != nil {
+		return nil, err
+	}
+	code, err := hex.DecodeString(linked[2:])
+	if err != nil || len(code) == 0 {
+		return nil, errors.Wrap(ErrInvalidBytecode, "bytecode is not valid hex")
+	}
+
+	input, err := contractAbi.Pack("", args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to pack constructor arguments")
+	}
+	return append(code, input...), nil
+}
+
+// DeployContract 部署合约并等待回执，返回合约地址和绑定的合约对象。
+// opts 可以为 nil；opts.Salt 不为 nil 时通过 CREATE2 工厂部署，地址可以预先用 ComputeCreate2Address 计算
+func (w *Wallet) DeployContract(ctx context.Context, contractAbi abi.ABI, bytecode string, opts *DeployOptions, args ...interface{}) (*DeployResult, error) {
+	if opts == nil {
+		opts = &DeployOptions{}
+	}
+
+	initCode, err := BuildDeployData(contractAbi, bytecode, opts.Libraries, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := opts.Nonce
+	if nonce == 0 {
+		if nonce, err = w.GetNonce(); err != nil {
+			return nil, err
+		}
+	}
+
+	var to *common.Address
+	var data []byte
+	var address common.Address
+	if opts.Salt != nil {
+		factory := opts.Factory
+		if factory == (common.Address{}) {
+			factory = common.HexToAddress(DeterministicDeployerAddress)
+		}
+		to = &factory
+		data = append(opts.Salt.Bytes(), initCode...)
+		address = ComputeCreate2Address(factory, *opts.Salt, initCode)
+	} else {
+		data = initCode
+		address = ComputeContractAddress(w.GetAddress(), nonce)
+	}
+
+	gasPrice := opts.GasPrice
+	if gasPrice == nil || gasPrice.Sign() == 0 {
+		if gasPrice, err = w.GetEthProvider().GetSuggestGasPrice(); err != nil {
+			return nil, err
+		}
+	}
+
+	gasLimit := opts.GasLimit
+	if gasLimit == 0 {
+		gasLimit, err = w.GetClient().EstimateGas(ctx, ethereum.CallMsg{
+			From:     w.GetAddress(),
+			To:       to,
+			GasPrice: gasPrice,
+			Value:    opts.Value,
+			Data:     data,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to estimate deployment gas")
+		}
+	}
+
+	var tx *types.Transaction
+	if to == nil {
+		tx, err = NewContractCreationTx(nonce, gasLimit, gasPrice, opts.Value, data)
+	} else {
+		tx, err = NewTx(*to, nonce, gasLimit, gasPrice, opts.Value, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx, err := w.SignTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.SendSignedTx(signedTx); err != nil {
+		return nil, err
+	}
+
+	receipt, err := bind.WaitMined(ctx, w.GetClient(), signedTx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, errors.Wrapf(ErrTransactionFailed, "deployment %s reverted", signedTx.Hash().Hex())
+	}
+	if opts.Salt != nil {
+		// 确认工厂确实在预期的 CREATE2 地址部署了代码（非标准工厂失败时可能不会 revert）
+		code, err := w.GetClient().CodeAt(ctx, address, receipt.BlockNumber)
+		if err != nil {
+			return nil, err
+		}
+		if len(code) == 0 {
+			return nil, errors.Wrapf(ErrTransactionFailed, "no code at CREATE2 address %s", address.Hex())
+		}
+	}
+
+	contract, err := NewBoundContract(address, contractAbi, w)
+	if err != nil {
+		return nil, err
+	}
+	return &DeployResult{
+		Address:  address,
+		Tx:       signedTx,
+		Receipt:  receipt,
+		Contract: contract,
+	}, nil
+}
+
+// libraryPlaceholders 库在字节码中可能的占位符
+func libraryPlaceholders(name string) []string {
+	placeholders := []string{"__$" + hex.EncodeToString(crypto.Keccak256([]byte(name)))[:34] + "$__"}
+	// 旧格式：库名截断或用下划线补齐到36个字符
+	legacy := name
+	if len(legacy) > 36 {
+		legacy = legacy[:36]
+	}
+	placeholders = append(placeholders, "__"+legacy+strings.Repeat("_", 36-len(legacy))+"__")
+	return placeholders
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := values[:0:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package etherkit
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestLinkBytecode(t *testing.T) {
+	mathLib := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	stringsLib := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	fqn := "contracts/Math.sol:Math"
+	newPlaceholder := "__$" + hex.EncodeToString(crypto.Keccak256([]byte(fqn)))[:34] + "$__"
+	legacyPlaceholder := "__Strings" + strings.Repeat("_", 36-len("Strings")) + "__"
+	bytecode := "0x6080" + newPlaceholder + "60" + legacyPlaceholder + "00"
+
+	linked, err := LinkBytecode(bytecode, map[string]common.Address{fqn: mathLib, "Strings": stringsLib})
+	if err != nil {
+		t.Fatalf("LinkBytecode() failed: %v", err)
+	}
+	expected := "0x6080" + hex.EncodeToString(mathLib.Bytes()) + "60" + hex.EncodeToString(stringsLib.Bytes()) + "00"
+	if linked != expected {
+		t.Errorf("LinkBytecode() = %s, expected %s", linked, expected)
+	}
+
+	_, err = LinkBytecode(bytecode, map[string]common.Address{fqn: mathLib})
+	if !errors.Is(err, ErrUnlinkedLibrary) || !strings.Contains(err.Error(), legacyPlaceholder) {
+		t.Errorf("Expected ErrUnlinkedLibrary for Strings, got %v", err)
+	}
+	if _, err := LinkBytecode(bytecode, nil); !errors.Is(err, ErrUnlinkedLibrary) {
+		t.Errorf("Expected ErrUnlinkedLibrary, got %v", err)
+	}
+}
+
+func TestBuildDeployData(t *testing.T) {
+	contractAbi, err := GetABI(`[{"inputs":[{"name":"owner","type":"address"},{"name":"supply","type":"uint256"}],"stateMutability":"nonpayable","type":"constructor"}]`)
+	if err != nil {
+		t.Fatalf("GetABI() failed: %v", err)
+	}
+	owner := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+	data, err := BuildDeployData(contractAbi, "0x6080604052", nil, owner, BigInt100)
+	if err != nil {
+		t.Fatalf("BuildDeployData() failed: %v", err)
+	}
+	if len(data) != 5+64 || !bytes.Equal(data[:5], common.FromHex("0x6080604052")) {
+		t.Errorf("Unexpected init code: %x", data)
+	}
+	if common.BytesToAddress(data[5:37]) != owner || data[len(data)-1] != 100 {
+		t.Errorf("Unexpected constructor arguments: %x", data[5:])
+	}
+
+	if _, err := BuildDeployData(contractAbi, "0xzz", nil, owner, BigInt100); !errors.Is(err, ErrInvalidBytecode) {
+		t.Errorf("Expected ErrInvalidBytecode, got %v", err)
+	}
+	if _, err := BuildDeployData(contractAbi, "0x6080", nil, owner); err == nil {
+		t.Error("Expected error for missing constructor argument")
+	}
+
+	tx, err := NewContractCreationTx(1, 100000, BigInt1, nil, data)
+	if err != nil {
+		t.Fatalf("NewContractCreationTx() failed: %v", err)
+	}
+	if tx.To() != nil || !bytes.Equal(tx.Data(), data) {
+		t.Error("Contract creation tx should have nil to address")
+	}
+}
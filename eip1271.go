@@ -0,0 +1,231 @@
+package etherkit
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+)
+
+//############ EIP-1271 / EIP-6492 ############
+
+const (
+	// EIP1271MagicValue isValidSignature(bytes32,bytes) 验证通过时返回的值
+	EIP1271MagicValue = "0x1626ba7e"
+	// EIP6492MagicSuffix EIP-6492 包装签名的32字节后缀
+	EIP6492MagicSuffix = "0x6492649264926492649264926492649264926492649264926492649264926492"
+)
+
+const eip1271ABI = `[{"inputs":[{"name":"hash","type":"bytes32"},{"name":"signature","type":"bytes"}],"name":"isValidSignature","outputs":[{"name":"magicValue","type":"bytes4"}],"stateMutability":"view","type":"function"}]`
+
+var (
+	eip1271Contract, _ = GetABI(eip1271ABI)
+	eip1271Magic       = hexutil.MustDecode(EIP1271MagicValue)
+	eip6492Suffix      = hexutil.MustDecode(EIP6492MagicSuffix)
+	eip6492Arguments   = abi.Arguments{
+		{Name: "factory", Type: mustNewType("address")},
+		{Name: "factoryCalldata", Type: mustNewType("bytes")},
+		{Name: "signature", Type: mustNewType("bytes")},
+	}
+)
+
+// SignatureVerifier 基于 Provider 的签名验证器。
+// 签名者地址有代码时通过 EIP-1271 的 isValidSignature 验证，否则使用 ECDSA 恢复地址；
+// 对于 EIP-6492 包装的未部署钱包签名，通过 deployless eth_call 先执行工厂调用再验证，适用于任意节点。
+type SignatureVerifier struct {
+	ep EtherProvider
+}
+
+// NewSignatureVerifier 新建一个签名验证器
+func NewSignatureVerifier(ep EtherProvider) *SignatureVerifier {
+	return &SignatureVerifier{ep: ep}
+}
+
+// VerifyHash 验证 signer 对32字节摘要 hash 的签名。签名无效时返回 false，
+// RPC 调用失败或 EIP-6492 包装格式错误时返回 error
+func (v *SignatureVerifier) VerifyHash(signer common.Address, hash common.Hash, signature []byte) (bool, error) {
+	if IsEIP6492Signature(signature) {
+		return v.verifyEIP6492(signer, hash, signature)
+	}
+
+	isContract, err := v.ep.IsContractAddress(signer)
+	if err != nil {
+		return false, err
+	}
+	if isContract {
+		return v.isValidSignature(signer, hash, signature)
+	}
+
+	_, err = VerifyHashSignature(signer, hash.Bytes(), signature)
+	return err == nil, nil
+}
+
+// VerifyMessage 验证 signer 对 EIP-191 personal_sign 消息的签名
+func (v *SignatureVerifier) VerifyMessage(signer common.Address, message, signature []byte) (bool, error) {
+	return v.VerifyHash(signer, HashPersonalMessage(message), signature)
+}
+
+// VerifyTypedData 验证 signer 对EIP-712结构化数据的签名
+func (v *SignatureVerifier) VerifyTypedData(signer common.Address, typedData *TypedData, signature []byte) (bool, error) {
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return false, err
+	}
+	return v.VerifyHash(signer, hash, signature)
+}
+
+// isValidSignature 调用合约的 isValidSignature(bytes32,bytes) 并检查返回的 magic value
+func (v *SignatureVerifier) isValidSignature(signer common.Address, hash common.Hash, signature []byte) (bool, error) {
+	input, err := BuildContractInputData(eip1271Contract, "isValidSignature", hash, signature)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := v.ep.GetEthClient().CallContract(context.Background(), ethereum.CallMsg{
+		To:   &signer,
+		Data: input,
+	}, nil)
+	if err != nil {
+		// 合约 revert 视为签名无效
+		var dataErr rpc.DataError
+		if errors.As(err, &dataErr) {
+			return false, nil
+		}
+		return false, err
+	}
+	return isEIP1271MagicValue(res), nil
+}
+
+// verifyEIP6492 验证EIP-6492包装的签名
+func (v *SignatureVerifier) verifyEIP6492(signer common.Address, hash common.Hash, signature []byte) (bool, error) {
+	factory, factoryCalldata, innerSignature, err := ParseEIP6492Signature(signature)
+	if err != nil {
+		return false, err
+	}
+
+	// 钱包已部署时直接验证内部签名
+	isContract, err := v.ep.IsContractAddress(signer)
+	if err != nil {
+		return false, err
+	}
+	if isContract {
+		valid, err := v.isValidSignature(signer, hash, innerSignature)
+		if err != nil || valid {
+			return valid, err
+		}
+	}
+
+	input, err := BuildContractInputData(eip1271Contract, "isValidSignature", hash, innerSignature)
+	if err != nil {
+		return false, err
+	}
+
+	// deployless eth_call：不指定 to，构造代码中先调用工厂部署钱包再验证签名，不会真正上链
+	res, err := v.ep.GetEthClient().CallContract(context.Background(), ethereum.CallMsg{
+		Data: buildEIP6492ValidatorCode(signer, factory, factoryCalldata, input),
+	}, nil)
+	if err != nil {
+		var dataErr rpc.DataError
+		if errors.As(err, &dataErr) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed to call EIP-6492 signature validator")
+	}
+	return len(res) == 1 && res[0] == 1, nil
+}
+
+// buildEIP6492ValidatorCode 构建 EIP-6492 deployless 验证使用的构造代码，
+// 工厂调用数据和 isValidSignature 调用数据附加在代码之后。执行流程：
+//
+//	CALL(factory, factoryCalldata)                      // 部署钱包，失败时忽略
+//	ok := STATICCALL(signer, isValidSignature(...))
+//	RETURN(ok && returndata[:4] == 0x1626ba7e ? 0x01 : 0x00)
+//
+// 返回的1字节作为"部署"出的合约代码，即 eth_call 的返回值
+func buildEIP6492ValidatorCode(signer, factory common.Address, factoryCalldata, input []byte) []byte {
+	build := func(codeLen int) []byte {
+		push4 := func(v int) []byte {
+			return []byte{0x63, byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+		}
+		var code []byte
+		// CODECOPY(0, codeLen, len(factoryCalldata))
+		code = append(code, push4(len(factoryCalldata))...)
+		code = append(code, push4(codeLen)...)
+		code = append(code, 0x60, 0x00, 0x39)
+		// POP(CALL(gas, factory, 0, 0, len(factoryCalldata), 0, 0))
+		code = append(code, 0x60, 0x00, 0x60, 0x00)
+		code = append(code, push4(len(factoryCalldata))...)
+		code = append(code, 0x60, 0x00, 0x60, 0x00, 0x73)
+		code = append(code, factory.Bytes()...)
+		code = append(code, 0x5a, 0xf1, 0x50)
+		// CODECOPY(0, codeLen+len(factoryCalldata), len(input))
+		code = append(code, push4(len(input))...)
+		code = append(code, push4(codeLen+len(factoryCalldata))...)
+		code = append(code, 0x60, 0x00, 0x39)
+		// ok := STATICCALL(gas, signer, 0, len(input), 0, 32)
+		code = append(code, 0x60, 0x20, 0x60, 0x00)
+		code = append(code, push4(len(input))...)
+		code = append(code, 0x60, 0x00, 0x73)
+		code = append(code, signer.Bytes()...)
+		code = append(code, 0x5a, 0xfa)
+		// ok && RETURNDATASIZE >= 32 && MLOAD(0) >> 224 == magic
+		code = append(code, 0x60, 0x20, 0x3d, 0x10, 0x15, 0x16)
+		code = append(code, 0x60, 0x00, 0x51, 0x60, 0xe0, 0x1c, 0x63)
+		code = append(code, eip1271Magic...)
+		code = append(code, 0x14, 0x16)
+		// MSTORE8(0, result); RETURN(0, 1)
+		code = append(code, 0x60, 0x00, 0x53, 0x60, 0x01, 0x60, 0x00, 0xf3)
+		return code
+	}
+
+	code := build(0)
+	code = build(len(code))
+	code = append(code, factoryCalldata...)
+	return append(code, input...)
+}
+
+// IsEIP6492Signature 判断签名是否是EIP-6492包装的签名
+func IsEIP6492Signature(signature []byte) bool {
+	return len(signature) > len(eip6492Suffix) && bytes.HasSuffix(signature, eip6492Suffix)
+}
+
+// ParseEIP6492Signature 解析EIP-6492包装的签名，返回工厂地址、工厂调用数据和原始签名
+func ParseEIP6492Signature(signature []byte) (factory common.Address, factoryCalldata, innerSignature []byte, err error) {
+	if !IsEIP6492Signature(signature) {
+		return common.Address{}, nil, nil, errors.Wrap(ErrInvalidSignature, "missing EIP-6492 magic suffix")
+	}
+
+	values, err := eip6492Arguments.Unpack(signature[:len(signature)-len(eip6492Suffix)])
+	if err != nil {
+		return common.Address{}, nil, nil, errors.Wrap(ErrInvalidSignature, err.Error())
+	}
+	return values[0].(common.Address), values[1].([]byte), values[2].([]byte), nil
+}
+
+// WrapEIP6492Signature 将未部署钱包的签名包装为EIP-6492格式
+func WrapEIP6492Signature(factory common.Address, factoryCalldata, signature []byte) ([]byte, error) {
+	packed, err := eip6492Arguments.Pack(factory, factoryCalldata, signature)
+	if err != nil {
+		return nil, err
+	}
+	return append(packed, eip6492Suffix...), nil
+}
+
+// isEIP1271MagicValue 检查 isValidSignature 的返回值，返回值为 abi 编码的 bytes4
+func isEIP1271MagicValue(res []byte) bool {
+	return len(res) >= 4 && bytes.Equal(res[:4], eip1271Magic)
+}
+
+// mustNewType 构建 abi.Type，类型字符串非法时 panic，只用于包内固定类型
+func mustNewType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
@@ -0,0 +1,238 @@
+package etherkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	// eip1271MagicWalletCode 钱包运行时代码：isValidSignature 总是返回 magic value
+	eip1271MagicWalletCode = append(append([]byte{0x63}, hexutil.MustDecode(EIP1271MagicValue)...),
+		0x60, 0xe0, 0x1b, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3)
+	// eip1271RevertWalletCode 钱包运行时代码：任何调用都 revert
+	eip1271RevertWalletCode = []byte{0x60, 0x00, 0x80, 0xfd}
+)
+
+// eip1271TestService 基于内存 EVM 状态的假节点，实现 eth_getCode 和 eth_call
+type eip1271TestService struct {
+	state *state.StateDB
+}
+
+type eip1271TestCallArgs struct {
+	To    *common.Address `json:"to"`
+	Input hexutil.Bytes   `json:"input"`
+}
+
+// eip1271TestRevertError 带 revert 数据的 JSON-RPC 错误，客户端收到后为 rpc.DataError
+type eip1271TestRevertError struct {
+	data []byte
+}
+
+func (e *eip1271TestRevertError) Error() string          { return "execution reverted" }
+func (e *eip1271TestRevertError) ErrorCode() int         { return 3 }
+func (e *eip1271TestRevertError) ErrorData() interface{} { return hexutil.Encode(e.data) }
+
+func (s *eip1271TestService) GetCode(address common.Address, block json.RawMessage) (hexutil.Bytes, error) {
+	return s.state.GetCode(address), nil
+}
+
+// Call 在状态副本上执行调用，to 为空时按 deployless 方式执行构造代码并返回"部署"出的代码
+func (s *eip1271TestService) Call(args eip1271TestCallArgs, block json.RawMessage) (hexutil.Bytes, error) {
+	cfg := &runtime.Config{State: s.state.Copy()}
+	var (
+		ret []byte
+		err error
+	)
+	if args.To == nil {
+		ret, _, _, err = runtime.Create(args.Input, cfg)
+	} else {
+		ret, _, err = runtime.Call(*args.To, args.Input, cfg)
+	}
+	if errors.Is(err, vm.ErrExecutionReverted) {
+		return nil, &eip1271TestRevertError{data: ret}
+	}
+	return ret, err
+}
+
+// newEIP1271TestVerifier 启动 HTTP 假节点，code 中的账户代码会预先写入状态
+func newEIP1271TestVerifier(t *testing.T, code map[common.Address][]byte) *SignatureVerifier {
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	if err != nil {
+		t.Fatalf("state.New() failed: %v", err)
+	}
+	for address, c := range code {
+		statedb.SetCode(address, c)
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &eip1271TestService{state: statedb}); err != nil {
+		t.Fatalf("RegisterName() failed: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+
+	provider, err := NewProvider(httpServer.URL)
+	if err != nil {
+		t.Fatalf("NewProvider() failed: %v", err)
+	}
+	return NewSignatureVerifier(provider)
+}
+
+func TestSignatureVerifier(t *testing.T) {
+	signer, err := NewSigner()
+	if err != nil {
+		t.Fatalf("NewSigner() failed: %v", err)
+	}
+	message := []byte("hello")
+	signature, err := signer.SignPersonalMessage(message)
+	if err != nil {
+		t.Fatalf("SignPersonalMessage() failed: %v", err)
+	}
+	hash := HashPersonalMessage(message)
+
+	// 工厂运行时代码：CREATE2(salt=0) 部署总是返回 magic value 的钱包
+	walletInitCode := append([]byte{
+		0x60, byte(len(eip1271MagicWalletCode)), 0x60, 0x0c, 0x60, 0x00, 0x39,
+		0x60, byte(len(eip1271MagicWalletCode)), 0x60, 0x00, 0xf3,
+	}, eip1271MagicWalletCode...)
+	factoryCode := append([]byte{
+		0x60, byte(len(walletInitCode)), 0x60, 0x12, 0x60, 0x00, 0x39,
+		0x60, 0x00, 0x60, byte(len(walletInitCode)), 0x60, 0x00, 0x60, 0x00, 0xf5, 0x50, 0x00,
+	}, walletInitCode...)
+
+	factory := common.HexToAddress("0x00000000000000000000000000000000000000fa")
+	emptyFactory := common.HexToAddress("0x00000000000000000000000000000000000000fb")
+	magicWallet := common.HexToAddress("0x0000000000000000000000000000000000000a01")
+	revertWallet := common.HexToAddress("0x0000000000000000000000000000000000000a02")
+	counterfactual := ComputeCreate2Address(factory, [32]byte{}, walletInitCode)
+
+	verifier := newEIP1271TestVerifier(t, map[common.Address][]byte{
+		factory:      factoryCode,
+		magicWallet:  eip1271MagicWalletCode,
+		revertWallet: eip1271RevertWalletCode,
+	})
+
+	wrapped, err := WrapEIP6492Signature(factory, hexutil.MustDecode("0xdeadbeef"), signature)
+	if err != nil {
+		t.Fatalf("WrapEIP6492Signature() failed: %v", err)
+	}
+	notDeployed, err := WrapEIP6492Signature(emptyFactory, nil, signature)
+	if err != nil {
+		t.Fatalf("WrapEIP6492Signature() failed: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		signer    common.Address
+		hash      common.Hash
+		signature []byte
+		expected  bool
+	}{
+		{"EOA valid signature", signer.GetAddress(), hash, signature, true},
+		{"EOA wrong hash", signer.GetAddress(), HashPersonalMessage([]byte("bye")), signature, false},
+		{"EIP-1271 magic value", magicWallet, hash, signature, true},
+		{"EIP-1271 revert", revertWallet, hash, signature, false},
+		{"EIP-6492 counterfactual wallet", counterfactual, hash, wrapped, true},
+		{"EIP-6492 factory deploys nothing", counterfactual, hash, notDeployed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := verifier.VerifyHash(tt.signer, tt.hash, tt.signature)
+			if err != nil {
+				t.Fatalf("VerifyHash() failed: %v", err)
+			}
+			if valid != tt.expected {
+				t.Errorf("VerifyHash() = %v, expected %v", valid, tt.expected)
+			}
+		})
+	}
+
+	// deployless 调用不会改变状态
+	if isContract, err := verifier.ep.IsContractAddress(counterfactual); err != nil || isContract {
+		t.Errorf("counterfactual wallet should stay undeployed: %v, %v", isContract, err)
+	}
+
+	corrupted := append([]byte{0x01, 0x02}, hexutil.MustDecode(EIP6492MagicSuffix)...)
+	if _, err := verifier.VerifyHash(counterfactual, hash, corrupted); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for malformed EIP-6492 signature, got %v", err)
+	}
+}
+
+func TestEIP6492Signature(t *testing.T) {
+	factory := common.HexToAddress("0x4e59b44847b379578588920cA78FbF26c0B4956C")
+	factoryCalldata := hexutil.MustDecode("0xdeadbeef")
+	innerSignature := bytes.Repeat([]byte{0x11}, crypto.SignatureLength)
+
+	wrapped, err := WrapEIP6492Signature(factory, factoryCalldata, innerSignature)
+	if err != nil {
+		t.Fatalf("WrapEIP6492Signature() failed: %v", err)
+	}
+
+	if !IsEIP6492Signature(wrapped) {
+		t.Error("Wrapped signature should be detected as EIP-6492")
+	}
+	if IsEIP6492Signature(innerSignature) {
+		t.Error("Plain signature should not be detected as EIP-6492")
+	}
+	if IsEIP6492Signature(hexutil.MustDecode(EIP6492MagicSuffix)) {
+		t.Error("Bare magic suffix should not be detected as EIP-6492")
+	}
+
+	gotFactory, gotCalldata, gotSignature, err := ParseEIP6492Signature(wrapped)
+	if err != nil {
+		t.Fatalf("ParseEIP6492Signature() failed: %v", err)
+	}
+	if gotFactory != factory {
+		t.Errorf("factory = %s, expected %s", gotFactory.Hex(), factory.Hex())
+	}
+	if !bytes.Equal(gotCalldata, factoryCalldata) {
+		t.Errorf("factory calldata = %x, expected %x", gotCalldata, factoryCalldata)
+	}
+	if !bytes.Equal(gotSignature, innerSignature) {
+		t.Errorf("signature = %x, expected %x", gotSignature, innerSignature)
+	}
+
+	if _, _, _, err := ParseEIP6492Signature(innerSignature); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+	corrupted := append([]byte{0x01, 0x02}, hexutil.MustDecode(EIP6492MagicSuffix)...)
+	if _, _, _, err := ParseEIP6492Signature(corrupted); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestIsEIP1271MagicValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		res      []byte
+		expected bool
+	}{
+		{"ABI encoded magic value", common.RightPadBytes(hexutil.MustDecode(EIP1271MagicValue), 32), true},
+		{"Wrong value", common.RightPadBytes(hexutil.MustDecode("0xffffffff"), 32), false},
+		{"Empty result", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := isEIP1271MagicValue(tt.res); result != tt.expected {
+				t.Errorf("isEIP1271MagicValue(%x) = %v, expected %v", tt.res, result, tt.expected)
+			}
+		})
+	}
+}
@@ -10,13 +10,20 @@ var (
 	ErrNetworkTimeout    = errors.New("network request timeout")
 
 	// 地址相关错误
-	ErrInvalidAddress = errors.New("invalid ethereum address")
-	ErrZeroAddress    = errors.New("address cannot be zero address")
+	ErrInvalidAddress       = errors.New("invalid ethereum address")
+	ErrZeroAddress          = errors.New("address cannot be zero address")
+	ErrInvalidVanityPattern = errors.New("invalid vanity address pattern")
+	ErrAddressPoisoning     = errors.New("possible address poisoning: look-alike address")
+	ErrAddressNotFound      = errors.New("address not found in address book")
+	ErrDuplicateLabel       = errors.New("duplicate address book label")
+	ErrInvalidLabel         = errors.New("invalid address book label")
 
 	// 私钥相关错误
 	ErrInvalidPrivateKey = errors.New("invalid private key")
 	ErrInvalidMnemonic   = errors.New("invalid mnemonic phrase")
 	ErrInvalidKeyFormat  = errors.New("invalid key format")
+	ErrInvalidPublicKey  = errors.New("invalid public key")
+	ErrSignerDestroyed   = errors.New("signer key material has been destroyed")
 
 	// 交易相关错误
 	ErrInsufficientFunds = errors.New("insufficient funds for transaction")
@@ -29,12 +36,34 @@ var (
 	ErrContractCall           = errors.New("contract call failed")
 	ErrInvalidABI             = errors.New("invalid contract ABI")
 	ErrInvalidContractAddress = errors.New("invalid contract address")
+	ErrInvalidBytecode        = errors.New("invalid contract bytecode")
+	ErrUnlinkedLibrary        = errors.New("bytecode contains unlinked library placeholders")
+	ErrMethodNotFound         = errors.New("method not found in contract ABI")
+	ErrEventNotFound          = errors.New("event not found in contract ABI")
+	ErrEventMismatch          = errors.New("log does not match event")
+	ErrReturnTypeMismatch     = errors.New("contract return values do not match target type")
+	ErrInvalidCallOptions     = errors.New("invalid contract call options")
+	ErrInvalidCalldata        = errors.New("invalid calldata")
+	ErrInvalidTextSignature   = errors.New("invalid function or event signature")
+	ErrInvalidABIValue        = errors.New("value does not match ABI type")
 
 	// 签名相关错误
 	ErrSignatureFailed             = errors.New("signature generation failed")
 	ErrInvalidSignature            = errors.New("invalid signature")
 	ErrSignatureVerificationFailed = errors.New("signature verification failed")
 
+	// SIWE (EIP-4361) 相关错误
+	ErrSiweInvalidMessage  = errors.New("invalid sign-in with ethereum message")
+	ErrSiweDomainMismatch  = errors.New("sign-in with ethereum domain mismatch")
+	ErrSiweNonceMismatch   = errors.New("sign-in with ethereum nonce mismatch")
+	ErrSiweChainIDMismatch = errors.New("sign-in with ethereum chain id mismatch")
+	ErrSiweExpired         = errors.New("sign-in with ethereum message expired")
+	ErrSiweNotYetValid     = errors.New("sign-in with ethereum message not yet valid")
+
+	// 秘密分享相关错误
+	ErrInvalidShare       = errors.New("invalid secret share")
+	ErrInsufficientShares = errors.New("insufficient secret shares")
+
 	// 钱包相关错误
 	ErrWalletClosed        = errors.New("wallet connection is closed")
 	ErrInvalidWalletConfig = errors.New("invalid wallet configuration")
@@ -149,9 +149,11 @@ func main() {
 	// 8. 使用合约调用接口查询余额
 	fmt.Println("\n8. 使用合约调用接口查询余额...")
 
-	// 获取 ERC20 ABI
-	abiString := `[{"inputs":[{"internalType":"address","name":"account","type":"address"}],"name":"balanceOf","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
-	contractAbi, err := etherkit.GetABI(abiString)
+	// 使用人类可读的片段构建 ERC20 ABI
+	contractAbi, err := etherkit.ParseHumanReadableABI(
+		"function balanceOf(address account) view returns (uint256)",
+		"function transfer(address to, uint256 amount) returns (bool)",
+	)
 	if err != nil {
 		log.Printf("❌ 解析ABI失败: %v", err)
 	} else {
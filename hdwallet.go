@@ -0,0 +1,451 @@
+package etherkit
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+//############ HD Wallet (BIP32/BIP44) ############
+
+// DerivationPathIndexPlaceholder 派生路径模板中账户序号的占位符
+const DerivationPathIndexPlaceholder = "{index}"
+
+// 常用钱包的派生路径模板
+const (
+	// DefaultDerivationPath BIP44 标准路径，MetaMask、Trezor、Ledger(Ethereum app 旧版默认) 使用
+	DefaultDerivationPath = "m/44'/60'/0'/0/{index}"
+	// LedgerLiveDerivationPath Ledger Live 使用的路径，每个账户使用不同的 account 层级
+	LedgerLiveDerivationPath = "m/44'/60'/{index}'/0/0"
+	// LedgerLegacyDerivationPath Ledger Chrome app / MyEtherWallet 旧版使用的路径
+	LedgerLegacyDerivationPath = "m/44'/60'/0'/{index}"
+	// EthereumClassicDerivationPath Ethereum Classic (coin type 61) 使用的路径
+	EthereumClassicDerivationPath = "m/44'/61'/0'/0/{index}"
+)
+
+// HDDerivationMode BIP32 私钥派生方式
+type HDDerivationMode int
+
+const (
+	// HDDerivationLegacy 默认的派生方式，与 go-ethereum-hdwallet（本库早期版本）一致。
+	// 派生 hardened 子密钥时父私钥不足32字节不补零（btcutil issue 172），
+	// 约1/128的助记词会得到与 MetaMask 等钱包不同的账户
+	HDDerivationLegacy HDDerivationMode = iota
+	// HDDerivationStandard 符合 BIP32 标准的派生方式，与 MetaMask、Trezor、Ledger 一致
+	HDDerivationStandard
+)
+
+// DefaultHDScanGapLimit 扫描已使用账户时，连续未使用账户达到该数量后停止
+const DefaultHDScanGapLimit = 20
+
+// DerivationPathForIndex 将路径模板中的 {index} 替换为账户序号
+func DerivationPathForIndex(pathTemplate string, index uint32) (string, error) {
+	if !strings.Contains(pathTemplate, DerivationPathIndexPlaceholder) {
+		return "", errors.Errorf("derivation path template %q has no %s placeholder", pathTemplate, DerivationPathIndexPlaceholder)
+	}
+	return strings.ReplaceAll(pathTemplate, DerivationPathIndexPlaceholder, strconv.FormatUint(uint64(index), 10)), nil
+}
+
+// BuildPrivateKeyFromMnemonicAndPath 使用助记词、BIP39 passphrase 和任意派生路径获得私钥，如 m/44'/60'/1'/0/0。
+// 使用 HDDerivationLegacy 派生方式
+func BuildPrivateKeyFromMnemonicAndPath(mnemonic, passphrase, path string) (*ecdsa.PrivateKey, error) {
+	return BuildPrivateKeyFromMnemonicAndPathWithMode(mnemonic, passphrase, path, HDDerivationLegacy)
+}
+
+// BuildPrivateKeyFromMnemonicAndPathWithMode 使用助记词、BIP39 passphrase、派生路径和指定的派生方式获得私钥
+func BuildPrivateKeyFromMnemonicAndPathWithMode(mnemonic, passphrase, path string, mode HDDerivationMode) (*ecdsa.PrivateKey, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return BuildPrivateKeyFromSeedAndPathWithMode(seed, path, mode)
+}
+
+// BuildPrivateKeyFromSeedAndPath 使用 BIP39 种子和任意派生路径获得私钥，使用 HDDerivationLegacy 派生方式
+func BuildPrivateKeyFromSeedAndPath(seed []byte, path string) (*ecdsa.PrivateKey, error) {
+	return BuildPrivateKeyFromSeedAndPathWithMode(seed, path, HDDerivationLegacy)
+}
+
+// BuildPrivateKeyFromSeedAndPathWithMode 使用 BIP39 种子、派生路径和指定的派生方式获得私钥
+func BuildPrivateKeyFromSeedAndPathWithMode(seed []byte, path string, mode HDDerivationMode) (*ecdsa.PrivateKey, error) {
+	master, err := newHDMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	return deriveHDPrivateKey(master, path, mode)
+}
+
+// newHDMasterKey 从种子生成 BIP32 主私钥
+func newHDMasterKey(seed []byte) (*hdkeychain.ExtendedKey, error) {
+	master, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create HD master key")
+	}
+	return master, nil
+}
+
+// deriveHDKey 按路径和派生方式从扩展密钥派生子密钥
+func deriveHDKey(key *hdkeychain.ExtendedKey, path accounts.DerivationPath, mode HDDerivationMode) (*hdkeychain.ExtendedKey, error) {
+	var err error
+	for _, n := range path {
+		if mode == HDDerivationStandard {
+			key, err = key.Derive(n)
+		} else {
+			key, err = key.DeriveNonStandard(n)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to derive HD key")
+		}
+	}
+	return key, nil
+}
+
+// deriveHDPrivateKey 按路径字符串派生私钥
+func deriveHDPrivateKey(master *hdkeychain.ExtendedKey, path string, mode HDDerivationMode) (*ecdsa.PrivateKey, error) {
+	derivationPath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse derivation path")
+	}
+	key, err := deriveHDKey(master, derivationPath, mode)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := key.ECPrivKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get private key from HD key")
+	}
+	return privateKey.ToECDSA(), nil
+}
+
+// HDAccount 扫描到的HD账户
+type HDAccount struct {
+	Index   uint32
+	Path    string
+	Address common.Address
+	Nonce   uint64
+	Balance *big.Int
+}
+
+// HDAccountManager 基于同一个种子和路径模板派生多个账户，派生出的 Signer 会被缓存
+type HDAccountManager struct {
+	mu           sync.Mutex
+	master       *hdkeychain.ExtendedKey
+	pathTemplate string
+	mode         HDDerivationMode
+	signers      map[uint32]*Signer
+}
+
+// NewHDAccountManager 使用助记词、BIP39 passphrase 和路径模板创建HD账户管理器。pathTemplate 为空时使用 DefaultDerivationPath
+func NewHDAccountManager(mnemonic, passphrase, pathTemplate string) (*HDAccountManager, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return NewHDAccountManagerFromSeed(seed, pathTemplate)
+}
+
+// NewHDAccountManagerFromSeed 使用 BIP39 种子和路径模板创建HD账户管理器
+func NewHDAccountManagerFromSeed(seed []byte, pathTemplate string) (*HDAccountManager, error) {
+	if pathTemplate == "" {
+		pathTemplate = DefaultDerivationPath
+	}
+	if _, err := DerivationPathForIndex(pathTemplate, 0); err != nil {
+		return nil, err
+	}
+	master, err := newHDMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	return &HDAccountManager{
+		master:       master,
+		pathTemplate: pathTemplate,
+		signers:      make(map[uint32]*Signer),
+	}, nil
+}
+
+// PathTemplate 获得路径模板
+func (m *HDAccountManager) PathTemplate() string {
+	return m.pathTemplate
+}
+
+// DerivationMode 获得派生方式
+func (m *HDAccountManager) DerivationMode() HDDerivationMode {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mode
+}
+
+// SetDerivationMode 设置派生方式（默认 HDDerivationLegacy），会清除缓存的 Signer
+func (m *HDAccountManager) SetDerivationMode(mode HDDerivationMode) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mode = mode
+	m.signers = make(map[uint32]*Signer)
+}
+
+// Path 获得第 index 个账户的派生路径
+func (m *HDAccountManager) Path(index uint32) string {
+	path, _ := DerivationPathForIndex(m.pathTemplate, index)
+	return path
+}
+
+// Signer 获得第 index 个账户的 Signer，缓存的 Signer 已被销毁时重新派生。
+// 管理器被 Destroy 后返回 ErrSignerDestroyed
+func (m *HDAccountManager) Signer(index uint32) (*Signer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.master == nil {
+		return nil, ErrSignerDestroyed
+	}
+	if signer, ok := m.signers[index]; ok {
+		if !signer.IsDestroyed() {
+			return signer, nil
+		}
+		delete(m.signers, index)
+	}
+
+	pk, err := deriveHDPrivateKey(m.master, m.Path(index), m.mode)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := NewSignerFromPrivateKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	m.signers[index] = signer
+	return signer, nil
+}
+
+// Signers 获得从 start 开始的 count 个账户的 Signer
+func (m *HDAccountManager) Signers(start, count uint32) ([]*Signer, error) {
+	signers := make([]*Signer, 0, count)
+	for i := uint32(0); i < count; i++ {
+		signer, err := m.Signer(start + i)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, signer)
+	}
+	return signers, nil
+}
+
+// Address 获得第 index 个账户的地址
+func (m *HDAccountManager) Address(index uint32) (common.Address, error) {
+	signer, err := m.Signer(index)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return signer.GetAddress(), nil
+}
+
+// ScanUsedAccounts 从序号0开始扫描，nonce 或余额大于0的账户视为已使用，
+// 连续 gapLimit 个未使用账户后停止。gapLimit 为0时使用 DefaultHDScanGapLimit
+func (m *HDAccountManager) ScanUsedAccounts(ep EtherProvider, gapLimit uint32) ([]HDAccount, error) {
+	if gapLimit == 0 {
+		gapLimit = DefaultHDScanGapLimit
+	}
+
+	var used []HDAccount
+	for index, gap := uint32(0), uint32(0); gap < gapLimit; index++ {
+		address, err := m.Address(index)
+		if err != nil {
+			return nil, err
+		}
+		nonce, err := ep.GetEthClient().NonceAt(context.Background(), address, nil)
+		if err != nil {
+			return nil, err
+		}
+		balance, err := ep.GetEthClient().BalanceAt(context.Background(), address, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if nonce == 0 && balance.Sign() == 0 {
+			gap++
+			continue
+		}
+		gap = 0
+		used = append(used, HDAccount{
+			Index:   index,
+			Path:    m.Path(index),
+			Address: address,
+			Nonce:   nonce,
+			Balance: balance,
+		})
+	}
+	return used, nil
+}
+
+// ClearCache 清除缓存的 Signer
+func (m *HDAccountManager) ClearCache() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.signers = make(map[uint32]*Signer)
+}
+
+// Destroy 清零主私钥并销毁所有缓存的 Signer，之后派生操作都会返回 ErrSignerDestroyed
+func (m *HDAccountManager) Destroy() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.master != nil {
+		m.master.Zero()
+		m.master = nil
+	}
+	for _, signer := range m.signers {
+		signer.Destroy()
+	}
+	m.signers = make(map[uint32]*Signer)
+}
+
+// IsDestroyed 管理器是否已经被销毁
+func (m *HDAccountManager) IsDestroyed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.master == nil
+}
+
+//############ Extended Public Key (xpub) ############
+
+// ExportExtendedPublicKey 导出助记词在 accountPath 处的扩展公钥(xpub)，如 m/44'/60'/0'/0。
+// 从该 xpub 派生的第 i 个子地址与 accountPath/i 处私钥对应的地址一致，使用 HDDerivationLegacy 派生方式
+func ExportExtendedPublicKey(mnemonic, passphrase, accountPath string) (string, error) {
+	return ExportExtendedPublicKeyWithMode(mnemonic, passphrase, accountPath, HDDerivationLegacy)
+}
+
+// ExportExtendedPublicKeyWithMode 使用指定的派生方式导出助记词在 accountPath 处的扩展公钥(xpub)
+func ExportExtendedPublicKeyWithMode(mnemonic, passphrase, accountPath string, mode HDDerivationMode) (string, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return "", err
+	}
+	master, err := newHDMasterKey(seed)
+	if err != nil {
+		return "", err
+	}
+	return exportExtendedPublicKey(master, accountPath, mode)
+}
+
+// ExtendedPublicKey 导出路径模板中 {index} 的父路径处的扩展公钥。
+// 要求 {index} 位于路径末尾且不是 hardened，如 DefaultDerivationPath
+func (m *HDAccountManager) ExtendedPublicKey() (string, error) {
+	suffix := "/" + DerivationPathIndexPlaceholder
+	if !strings.HasSuffix(m.pathTemplate, suffix) {
+		return "", errors.Errorf("derivation path template %q does not end with a non-hardened %s", m.pathTemplate, DerivationPathIndexPlaceholder)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.master == nil {
+		return "", ErrSignerDestroyed
+	}
+	return exportExtendedPublicKey(m.master, strings.TrimSuffix(m.pathTemplate, suffix), m.mode)
+}
+
+func exportExtendedPublicKey(master *hdkeychain.ExtendedKey, accountPath string, mode HDDerivationMode) (string, error) {
+	derivationPath, err := accounts.ParseDerivationPath(accountPath)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse derivation path")
+	}
+	key, err := deriveHDKey(master, derivationPath, mode)
+	if err != nil {
+		return "", err
+	}
+	pub, err := key.Neuter()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to neuter HD key")
+	}
+	return pub.String(), nil
+}
+
+// WatchOnlyAccount 只持有扩展公钥的账户，只能派生非 hardened 路径的子地址，无法签名
+type WatchOnlyAccount struct {
+	key *hdkeychain.ExtendedKey
+}
+
+// NewWatchOnlyAccount 从扩展公钥(xpub)创建只读账户，传入扩展私钥会返回错误
+func NewWatchOnlyAccount(xpub string) (*WatchOnlyAccount, error) {
+	key, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidKeyFormat, err.Error())
+	}
+	if key.IsPrivate() {
+		return nil, errors.Wrap(ErrInvalidKeyFormat, "extended private key is not allowed for watch-only account")
+	}
+	return &WatchOnlyAccount{key: key}, nil
+}
+
+// String 获得扩展公钥
+func (a *WatchOnlyAccount) String() string {
+	return a.key.String()
+}
+
+// Address 获得第 index 个子地址
+func (a *WatchOnlyAccount) Address(index uint32) (common.Address, error) {
+	return a.DeriveAddress(strconv.FormatUint(uint64(index), 10))
+}
+
+// Addresses 获得从 start 开始的 count 个子地址
+func (a *WatchOnlyAccount) Addresses(start, count uint32) ([]common.Address, error) {
+	addresses := make([]common.Address, 0, count)
+	for i := uint32(0); i < count; i++ {
+		address, err := a.Address(start + i)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+// DeriveAddress 按相对路径派生子地址，如 "5"、"0/5" 或 "m/0/5"
+func (a *WatchOnlyAccount) DeriveAddress(path string) (common.Address, error) {
+	publicKey, err := a.DerivePublicKey(path)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return PublicKeyBytesToAddress(publicKey), nil
+}
+
+// DerivePublicKey 按相对路径派生子公钥，返回65字节未压缩公钥（04开头）
+func (a *WatchOnlyAccount) DerivePublicKey(path string) ([]byte, error) {
+	relativePath, err := parseRelativeDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	// 公钥派生不受 issue 172 影响，两种派生方式结果一致
+	key, err := deriveHDKey(a.key, relativePath, HDDerivationStandard)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := key.ECPubKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get public key from HD key")
+	}
+	return publicKey.SerializeUncompressed(), nil
+}
+
+// parseRelativeDerivationPath 解析非 hardened 的相对路径
+func parseRelativeDerivationPath(path string) (accounts.DerivationPath, error) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "m/")
+	var result accounts.DerivationPath
+	for _, component := range strings.Split(path, "/") {
+		if strings.HasSuffix(component, "'") {
+			return nil, errors.Errorf("hardened derivation %q is not possible from an extended public key", component)
+		}
+		n, err := strconv.ParseUint(component, 10, 32)
+		if err != nil || n >= hdkeychain.HardenedKeyStart {
+			return nil, errors.Errorf("invalid derivation path component %q", component)
+		}
+		result = append(result, uint32(n))
+	}
+	return result, nil
+}
@@ -0,0 +1,294 @@
+package etherkit
+
+import (
+	"errors"
+	"testing"
+)
+
+const hdTestMnemonic = "test test test test test test test test test test test junk"
+
+func TestDerivationPathForIndex(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    string
+		index       uint32
+		expected    string
+		shouldError bool
+	}{
+		{"Default path", DefaultDerivationPath, 3, "m/44'/60'/0'/0/3", false},
+		{"Ledger Live path", LedgerLiveDerivationPath, 2, "m/44'/60'/2'/0/0", false},
+		{"Ledger legacy path", LedgerLegacyDerivationPath, 1, "m/44'/60'/0'/1", false},
+		{"No placeholder", "m/44'/60'/0'/0/0", 1, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := DerivationPathForIndex(tt.template, tt.index)
+			if tt.shouldError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if path != tt.expected {
+				t.Errorf("DerivationPathForIndex() = %s, expected %s", path, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildPrivateKeyFromMnemonicAndPath(t *testing.T) {
+	// Hardhat/Anvil 默认账户
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"m/44'/60'/0'/0/0", "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"},
+		{"m/44'/60'/0'/0/1", "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"},
+		{"m/44'/60'/0'/0/2", "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			pk, err := BuildPrivateKeyFromMnemonicAndPath(hdTestMnemonic, "", tt.path)
+			if err != nil {
+				t.Fatalf("BuildPrivateKeyFromMnemonicAndPath() failed: %v", err)
+			}
+			if address := PrivateKeyToAddress(pk).Hex(); address != tt.expected {
+				t.Errorf("address = %s, expected %s", address, tt.expected)
+			}
+		})
+	}
+
+	if _, err := BuildPrivateKeyFromMnemonicAndPath(hdTestMnemonic, "", "invalid/path"); err == nil {
+		t.Error("Expected error for invalid path")
+	}
+}
+
+func TestHDDerivationModeIssue172(t *testing.T) {
+	// 该助记词在 m/44'/60'/0' 处的父私钥不足32字节，受 btcutil issue 172 影响
+	mnemonic := "sound practice disease erupt basket pumpkin truck file gorilla behave find exchange napkin boy congress address city net prosper crop chair marine chase seven"
+	legacy := "0x3943412CBEEEd4b68d73382b136F36b0CB82F481"
+	standard := "0x98e440675eFF3041D20bECb7fE7e81746A431b6d"
+
+	pk, err := BuildPrivateKeyFromMnemonicAndAccountId(mnemonic, 0)
+	if err != nil {
+		t.Fatalf("BuildPrivateKeyFromMnemonicAndAccountId() failed: %v", err)
+	}
+	if address := PrivateKeyToAddress(pk).Hex(); address != legacy {
+		t.Errorf("BuildPrivateKeyFromMnemonicAndAccountId() address = %s, expected %s", address, legacy)
+	}
+
+	signer, err := NewSignerFromMnemonic(mnemonic)
+	if err != nil {
+		t.Fatalf("NewSignerFromMnemonic() failed: %v", err)
+	}
+	if address := signer.GetAddress().Hex(); address != legacy {
+		t.Errorf("NewSignerFromMnemonic() address = %s, expected %s", address, legacy)
+	}
+
+	pk, err = BuildPrivateKeyFromMnemonicAndPathWithMode(mnemonic, "", "m/44'/60'/0'/0/0", HDDerivationStandard)
+	if err != nil {
+		t.Fatalf("BuildPrivateKeyFromMnemonicAndPathWithMode() failed: %v", err)
+	}
+	if address := PrivateKeyToAddress(pk).Hex(); address != standard {
+		t.Errorf("standard derivation address = %s, expected %s", address, standard)
+	}
+
+	manager, err := NewHDAccountManager(mnemonic, "", "")
+	if err != nil {
+		t.Fatalf("NewHDAccountManager() failed: %v", err)
+	}
+	if address, _ := manager.Address(0); address.Hex() != legacy {
+		t.Errorf("manager legacy address = %s, expected %s", address.Hex(), legacy)
+	}
+	manager.SetDerivationMode(HDDerivationStandard)
+	if address, _ := manager.Address(0); address.Hex() != standard {
+		t.Errorf("manager standard address = %s, expected %s", address.Hex(), standard)
+	}
+
+	for _, tt := range []struct {
+		mode     HDDerivationMode
+		expected string
+	}{
+		{HDDerivationLegacy, legacy},
+		{HDDerivationStandard, standard},
+	} {
+		xpub, err := ExportExtendedPublicKeyWithMode(mnemonic, "", "m/44'/60'/0'/0", tt.mode)
+		if err != nil {
+			t.Fatalf("ExportExtendedPublicKeyWithMode() failed: %v", err)
+		}
+		account, err := NewWatchOnlyAccount(xpub)
+		if err != nil {
+			t.Fatalf("NewWatchOnlyAccount() failed: %v", err)
+		}
+		if address, _ := account.Address(0); address.Hex() != tt.expected {
+			t.Errorf("watch-only address (mode %d) = %s, expected %s", tt.mode, address.Hex(), tt.expected)
+		}
+	}
+}
+
+func TestHDAccountManager(t *testing.T) {
+	manager, err := NewHDAccountManager(hdTestMnemonic, "", "")
+	if err != nil {
+		t.Fatalf("NewHDAccountManager() failed: %v", err)
+	}
+	if manager.PathTemplate() != DefaultDerivationPath {
+		t.Errorf("PathTemplate() = %s, expected %s", manager.PathTemplate(), DefaultDerivationPath)
+	}
+
+	signers, err := manager.Signers(0, 3)
+	if err != nil {
+		t.Fatalf("Signers() failed: %v", err)
+	}
+	expected := []string{
+		"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		"0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC",
+	}
+	for i, signer := range signers {
+		if signer.GetAddress().Hex() != expected[i] {
+			t.Errorf("Signer(%d) = %s, expected %s", i, signer.GetAddress().Hex(), expected[i])
+		}
+	}
+
+	// 缓存命中返回同一个 Signer
+	cached, err := manager.Signer(1)
+	if err != nil {
+		t.Fatalf("Signer() failed: %v", err)
+	}
+	if cached != signers[1] {
+		t.Error("Signer(1) should be served from cache")
+	}
+	manager.ClearCache()
+	fresh, err := manager.Signer(1)
+	if err != nil {
+		t.Fatalf("Signer() failed: %v", err)
+	}
+	if fresh == signers[1] || fresh.GetAddress() != signers[1].GetAddress() {
+		t.Error("Signer(1) should be re-derived after ClearCache")
+	}
+
+	// 已销毁的缓存 Signer 会被重新派生
+	fresh.Destroy()
+	rederived, err := manager.Signer(1)
+	if err != nil {
+		t.Fatalf("Signer() failed: %v", err)
+	}
+	if rederived == fresh || rederived.IsDestroyed() || rederived.GetAddress() != signers[1].GetAddress() {
+		t.Error("destroyed Signer(1) should be evicted from cache")
+	}
+
+	// Destroy 清零主私钥并销毁缓存的 Signer
+	manager.Destroy()
+	if !manager.IsDestroyed() || !rederived.IsDestroyed() {
+		t.Error("manager and cached signers should be destroyed")
+	}
+	if _, err := manager.Signer(0); !errors.Is(err, ErrSignerDestroyed) {
+		t.Errorf("Signer() after Destroy error = %v, expected ErrSignerDestroyed", err)
+	}
+	if _, err := manager.ExtendedPublicKey(); !errors.Is(err, ErrSignerDestroyed) {
+		t.Errorf("ExtendedPublicKey() after Destroy error = %v, expected ErrSignerDestroyed", err)
+	}
+
+	// Ledger Live 路径与直接派生一致
+	ledger, err := NewHDAccountManager(hdTestMnemonic, "", LedgerLiveDerivationPath)
+	if err != nil {
+		t.Fatalf("NewHDAccountManager() failed: %v", err)
+	}
+	address, err := ledger.Address(1)
+	if err != nil {
+		t.Fatalf("Address() failed: %v", err)
+	}
+	pk, err := BuildPrivateKeyFromMnemonicAndPath(hdTestMnemonic, "", "m/44'/60'/1'/0/0")
+	if err != nil {
+		t.Fatalf("BuildPrivateKeyFromMnemonicAndPath() failed: %v", err)
+	}
+	if address != PrivateKeyToAddress(pk) {
+		t.Errorf("Ledger Live address = %s, expected %s", address.Hex(), PrivateKeyToAddress(pk).Hex())
+	}
+	if ledger.Path(1) != "m/44'/60'/1'/0/0" {
+		t.Errorf("Path(1) = %s", ledger.Path(1))
+	}
+
+	if _, err := NewHDAccountManager(hdTestMnemonic, "", "m/44'/60'/0'/0/0"); err == nil {
+		t.Error("Expected error for template without placeholder")
+	}
+}
+
+func TestWatchOnlyAccount(t *testing.T) {
+	xpub, err := ExportExtendedPublicKey(hdTestMnemonic, "", "m/44'/60'/0'/0")
+	if err != nil {
+		t.Fatalf("ExportExtendedPublicKey() failed: %v", err)
+	}
+	if xpub[:4] != "xpub" {
+		t.Errorf("xpub = %s, expected xpub prefix", xpub)
+	}
+
+	manager, err := NewHDAccountManager(hdTestMnemonic, "", DefaultDerivationPath)
+	if err != nil {
+		t.Fatalf("NewHDAccountManager() failed: %v", err)
+	}
+	managerXpub, err := manager.ExtendedPublicKey()
+	if err != nil {
+		t.Fatalf("ExtendedPublicKey() failed: %v", err)
+	}
+	if managerXpub != xpub {
+		t.Errorf("ExtendedPublicKey() = %s, expected %s", managerXpub, xpub)
+	}
+
+	account, err := NewWatchOnlyAccount(xpub)
+	if err != nil {
+		t.Fatalf("NewWatchOnlyAccount() failed: %v", err)
+	}
+	if account.String() != xpub {
+		t.Errorf("String() = %s, expected %s", account.String(), xpub)
+	}
+
+	// 只读账户派生的地址与私钥派生的地址一致
+	addresses, err := account.Addresses(0, 5)
+	if err != nil {
+		t.Fatalf("Addresses() failed: %v", err)
+	}
+	for i, address := range addresses {
+		expected, err := manager.Address(uint32(i))
+		if err != nil {
+			t.Fatalf("Address() failed: %v", err)
+		}
+		if address != expected {
+			t.Errorf("Address(%d) = %s, expected %s", i, address.Hex(), expected.Hex())
+		}
+	}
+
+	for _, path := range []string{"3", "m/3"} {
+		address, err := account.DeriveAddress(path)
+		if err != nil {
+			t.Fatalf("DeriveAddress(%s) failed: %v", path, err)
+		}
+		if address != addresses[3] {
+			t.Errorf("DeriveAddress(%s) = %s, expected %s", path, address.Hex(), addresses[3].Hex())
+		}
+	}
+
+	for _, path := range []string{"0'", "x", "", "0/-1"} {
+		if _, err := account.DeriveAddress(path); err == nil {
+			t.Errorf("DeriveAddress(%q) expected error", path)
+		}
+	}
+
+	// Ledger Live 模板的 {index} 是 hardened 的，无法导出 xpub
+	ledger, err := NewHDAccountManager(hdTestMnemonic, "", LedgerLiveDerivationPath)
+	if err != nil {
+		t.Fatalf("NewHDAccountManager() failed: %v", err)
+	}
+	if _, err := ledger.ExtendedPublicKey(); err == nil {
+		t.Error("Expected error for hardened index template")
+	}
+
+	if _, err := NewWatchOnlyAccount("not an xpub"); err == nil {
+		t.Error("Expected error for invalid xpub")
+	}
+}
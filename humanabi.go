@@ -0,0 +1,382 @@
+package etherkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/pkg/errors"
+)
+
+//############ Human-Readable ABI ############
+
+// humanABIEntry JSON ABI 的一个条目，用于将人类可读的片段转换为 abi.ABI
+type humanABIEntry struct {
+	Type            string                   `json:"type"`
+	Name            string                   `json:"name,omitempty"`
+	Inputs          []abi.ArgumentMarshaling `json:"inputs"`
+	Outputs         []abi.ArgumentMarshaling `json:"outputs,omitempty"`
+	StateMutability string                   `json:"stateMutability,omitempty"`
+	Anonymous       bool                     `json:"anonymous,omitempty"`
+}
+
+// ParseHumanReadableABI 从人类可读的片段构建 abi.ABI，每个片段形如：
+//
+//	function transfer(address to, uint256 amount) returns (bool)
+//	function balanceOf(address) view returns (uint256)
+//	event Transfer(address indexed from, address indexed to, uint256 value)
+//	error InsufficientBalance(uint256 available, uint256 required)
+//	constructor(string name, string symbol)
+//	function aggregate3(tuple(address target, bool allowFailure, bytes callData)[] calls) payable returns (tuple(bool success, bytes returnData)[])
+//
+// tuple 也可以省略 tuple 关键字写作 (address,bool)；uint、int 视为 uint256、int256。
+// 未命名的 tuple 组件依次命名为 field0、field1...
+func ParseHumanReadableABI(fragments ...string) (abi.ABI, error) {
+	entries := make([]humanABIEntry, 0, len(fragments))
+	for _, fragment := range fragments {
+		fragment = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(fragment), ";"))
+		if fragment == "" || strings.HasPrefix(fragment, "//") {
+			continue
+		}
+		entry, err := parseHumanABIFragment(fragment)
+		if err != nil {
+			return abi.ABI{}, errors.Wrapf(ErrInvalidABI, "%q: %v", fragment, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return abi.ABI{}, err
+	}
+	contractAbi, err := abi.JSON(strings.NewReader(string(data)))
+	if err != nil {
+		return abi.ABI{}, errors.Wrap(ErrInvalidABI, err.Error())
+	}
+	return contractAbi, nil
+}
+
+// FormatHumanReadableABI 将 abi.ABI 输出为人类可读的片段，顺序为构造函数、方法、事件、错误、fallback、receive，
+// 同类按签名排序
+func FormatHumanReadableABI(contractAbi abi.ABI) []string {
+	var fragments []string
+	if len(contractAbi.Constructor.Inputs) > 0 || contractAbi.Constructor.IsPayable() {
+		fragments = append(fragments, "constructor("+formatHumanArguments(contractAbi.Constructor.Inputs, false)+")"+payableSuffix(contractAbi.Constructor))
+	}
+
+	var functions, events, abiErrors []string
+	for _, method := range contractAbi.Methods {
+		fragment := "function " + method.RawName + "(" + formatHumanArguments(method.Inputs, false) + ")"
+		switch method.StateMutability {
+		case "view", "pure", "payable":
+			fragment += " " + method.StateMutability
+		}
+		if len(method.Outputs) > 0 {
+			fragment += " returns (" + formatHumanArguments(method.Outputs, false) + ")"
+		}
+		functions = append(functions, fragment)
+	}
+	for _, event := range contractAbi.Events {
+		fragment := "event " + event.RawName + "(" + formatHumanArguments(event.Inputs, true) + ")"
+		if event.Anonymous {
+			fragment += " anonymous"
+		}
+		events = append(events, fragment)
+	}
+	for _, e := range contractAbi.Errors {
+		abiErrors = append(abiErrors, "error "+e.Name+"("+formatHumanArguments(e.Inputs, false)+")")
+	}
+	for _, group := range [][]string{functions, events, abiErrors} {
+		sort.Strings(group)
+		fragments = append(fragments, group...)
+	}
+
+	if contractAbi.HasFallback() {
+		fragments = append(fragments, "fallback() external"+payableSuffix(contractAbi.Fallback))
+	}
+	if contractAbi.HasReceive() {
+		fragments = append(fragments, "receive() external payable")
+	}
+	return fragments
+}
+
+func parseHumanABIFragment(fragment string) (humanABIEntry, error) {
+	kind := "function"
+	if i := strings.IndexAny(fragment, " \t("); i > 0 {
+		switch keyword := fragment[:i]; keyword {
+		case "function", "event", "error":
+			kind, fragment = keyword, strings.TrimSpace(fragment[i:])
+		case "constructor", "fallback", "receive":
+			kind = keyword
+			fragment = strings.TrimSpace(fragment[i:])
+		}
+	}
+
+	var name string
+	if kind == "function" || kind == "event" || kind == "error" {
+		open := strings.IndexByte(fragment, '(')
+		if open <= 0 || !identifierRegexp.MatchString(strings.TrimSpace(fragment[:open])) {
+			return humanABIEntry{}, errors.New("missing name")
+		}
+		name, fragment = strings.TrimSpace(fragment[:open]), fragment[open:]
+	}
+
+	params, rest, err := cutParenthesized(fragment)
+	if err != nil {
+		return humanABIEntry{}, err
+	}
+	entry := humanABIEntry{Type: kind, Name: name, Inputs: []abi.ArgumentMarshaling{}}
+	if entry.Inputs, err = parseHumanArguments(params, kind == "event"); err != nil {
+		return humanABIEntry{}, err
+	}
+
+	// 修饰符与返回值
+	if kind == "function" || kind == "constructor" || kind == "fallback" || kind == "receive" {
+		entry.StateMutability = "nonpayable"
+	}
+	if kind == "receive" {
+		entry.StateMutability = "payable"
+	}
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		word := rest
+		if i := strings.IndexAny(rest, " \t("); i >= 0 {
+			word = rest[:i]
+		}
+		rest = rest[len(word):]
+		switch word {
+		case "view", "pure", "payable", "nonpayable":
+			entry.StateMutability = word
+		case "constant":
+			entry.StateMutability = "view"
+		case "external", "public", "virtual", "override":
+		case "anonymous":
+			if kind != "event" {
+				return humanABIEntry{}, errors.New("only events can be anonymous")
+			}
+			entry.Anonymous = true
+		case "returns":
+			if kind != "function" {
+				return humanABIEntry{}, errors.Errorf("%s cannot have return values", kind)
+			}
+			var outputs string
+			if outputs, rest, err = cutParenthesized(strings.TrimSpace(rest)); err != nil {
+				return humanABIEntry{}, err
+			}
+			if entry.Outputs, err = parseHumanArguments(outputs, false); err != nil {
+				return humanABIEntry{}, err
+			}
+		default:
+			return humanABIEntry{}, errors.Errorf("unexpected %q", word)
+		}
+	}
+	if kind == "function" && entry.Outputs == nil {
+		entry.Outputs = []abi.ArgumentMarshaling{}
+	}
+	return entry, nil
+}
+
+// parseHumanArguments 解析逗号分隔的参数列表，如 "address indexed from, uint256 value"
+func parseHumanArguments(params string, allowIndexed bool) ([]abi.ArgumentMarshaling, error) {
+	items, err := splitTypeList(strings.TrimSpace(params))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]abi.ArgumentMarshaling, 0, len(items))
+	for _, item := range items {
+		arg, err := parseHumanArgument(strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		if arg.Indexed && !allowIndexed {
+			return nil, errors.Errorf("unexpected indexed in %q", item)
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+// parseHumanArgument 解析单个参数，如 "address indexed from"、"tuple(address a, bytes b)[] calls"
+func parseHumanArgument(param string) (abi.ArgumentMarshaling, error) {
+	if param == "" {
+		return abi.ArgumentMarshaling{}, errors.New("empty parameter")
+	}
+
+	var arg abi.ArgumentMarshaling
+	var rest string
+	if strings.HasPrefix(param, "tuple(") || strings.HasPrefix(param, "(") {
+		inner, after, err := cutParenthesized(strings.TrimPrefix(param, "tuple"))
+		if err != nil {
+			return arg, err
+		}
+		suffix := after
+		if i := strings.IndexAny(after, " \t"); i >= 0 {
+			suffix, rest = after[:i], after[i:]
+		}
+		if arg.Components, err = parseHumanArguments(inner, false); err != nil {
+			return arg, err
+		}
+		for i := range arg.Components {
+			if arg.Components[i].Name == "" {
+				arg.Components[i].Name = fmt.Sprintf("field%d", i)
+			}
+		}
+		arg.Type = "tuple" + suffix
+	} else {
+		fields := strings.Fields(param)
+		arg.Type, rest = canonicalTypeName(fields[0]), strings.Join(fields[1:], " ")
+		if !isElementaryType(arg.Type) {
+			return arg, errors.Errorf("invalid type %q", fields[0])
+		}
+	}
+
+	for _, word := range strings.Fields(rest) {
+		switch word {
+		case "indexed":
+			arg.Indexed = true
+		case "memory", "calldata", "storage", "payable":
+		default:
+			if arg.Name != "" || !identifierRegexp.MatchString(word) {
+				return arg, errors.Errorf("unexpected %q in parameter %q", word, param)
+			}
+			arg.Name = word
+		}
+	}
+	return arg, nil
+}
+
+// canonicalTypeName 将基本类型的别名转换为规范形式：uint -> uint256，int -> int256，byte -> bytes1，保留数组后缀
+func canonicalTypeName(typ string) string {
+	base, suffix := typ, ""
+	if i := strings.IndexByte(typ, '['); i >= 0 {
+		base, suffix = typ[:i], typ[i:]
+	}
+	switch base {
+	case "uint":
+		base = "uint256"
+	case "int":
+		base = "int256"
+	case "byte":
+		base = "bytes1"
+	}
+	return base + suffix
+}
+
+// isElementaryType 检查基本类型（可带数组后缀）是否合法，如 uint7、bytes33 不合法
+func isElementaryType(typ string) bool {
+	base := typ
+	if i := strings.IndexByte(typ, '['); i >= 0 {
+		base = typ[:i]
+	}
+	switch base {
+	case "address", "bool", "string", "bytes", "function":
+		return true
+	}
+	for _, prefix := range []string{"uint", "int", "bytes"} {
+		if !strings.HasPrefix(base, prefix) {
+			continue
+		}
+		size, err := strconv.Atoi(base[len(prefix):])
+		if err != nil || strconv.Itoa(size) != base[len(prefix):] {
+			return false
+		}
+		if prefix == "bytes" {
+			return size >= 1 && size <= 32
+		}
+		return size >= 8 && size <= 256 && size%8 == 0
+	}
+	return false
+}
+
+// splitTypeList 按顶层逗号拆分类型列表
+func splitTypeList(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var types []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth < 0 {
+				return nil, errors.Errorf("unbalanced parentheses in %s", s)
+			}
+		case ',':
+			if depth == 0 {
+				types = append(types, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, errors.Errorf("unbalanced parentheses in %s", s)
+	}
+	types = append(types, s[start:])
+	for _, t := range types {
+		if t == "" {
+			return nil, errors.Errorf("empty type in %s", s)
+		}
+	}
+	return types, nil
+}
+
+// cutParenthesized 返回开头括号内的内容和括号之后的部分
+func cutParenthesized(s string) (inner, rest string, err error) {
+	if !strings.HasPrefix(s, "(") {
+		return "", "", errors.Errorf("expected ( in %q", s)
+	}
+	depth := 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return s[1:i], s[i+1:], nil
+			}
+		}
+	}
+	return "", "", errors.Errorf("unbalanced parentheses in %q", s)
+}
+
+func formatHumanArguments(args abi.Arguments, withIndexed bool) string {
+	items := make([]string, len(args))
+	for i, arg := range args {
+		item := formatHumanType(arg.Type)
+		if withIndexed && arg.Indexed {
+			item += " indexed"
+		}
+		if arg.Name != "" {
+			item += " " + arg.Name
+		}
+		items[i] = item
+	}
+	return strings.Join(items, ", ")
+}
+
+func formatHumanType(typ abi.Type) string {
+	switch typ.T {
+	case abi.TupleTy:
+		components := make([]string, len(typ.TupleElems))
+		for i, elem := range typ.TupleElems {
+			components[i] = formatHumanType(*elem) + " " + typ.TupleRawNames[i]
+		}
+		return "tuple(" + strings.Join(components, ", ") + ")"
+	case abi.SliceTy:
+		return formatHumanType(*typ.Elem) + "[]"
+	case abi.ArrayTy:
+		return formatHumanType(*typ.Elem) + fmt.Sprintf("[%d]", typ.Size)
+	}
+	return typ.String()
+}
+
+func payableSuffix(method abi.Method) string {
+	if method.IsPayable() {
+		return " payable"
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package etherkit
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/guanzhenxing/go-evm-kit/contracts/erc20"
+)
+
+func TestParseHumanReadableABI(t *testing.T) {
+	contractAbi, err := ParseHumanReadableABI(
+		"constructor(string name, string symbol)",
+		"function transfer(address to, uint amount) returns (bool)",
+		"function balanceOf(address owner) external view returns (uint256)",
+		"event Transfer(address indexed from, address indexed to, uint256 value)",
+		"error InsufficientBalance(uint256 available, uint256 required)",
+		"function aggregate3(tuple(address target, bool allowFailure, bytes callData)[] calls) payable returns ((bool success, bytes returnData)[] returnData)",
+		"event Ping(address indexed sender) anonymous",
+		"receive() external payable",
+	)
+	if err != nil {
+		t.Fatalf("ParseHumanReadableABI() failed: %v", err)
+	}
+
+	erc20Abi, _ := erc20.IERC20MetaData.GetAbi()
+	for _, name := range []string{"transfer", "balanceOf"} {
+		if !reflect.DeepEqual(contractAbi.Methods[name].ID, erc20Abi.Methods[name].ID) {
+			t.Errorf("%s selector mismatch", name)
+		}
+	}
+	if contractAbi.Events["Transfer"].ID != erc20Abi.Events["Transfer"].ID || !contractAbi.Events["Ping"].Anonymous {
+		t.Error("Unexpected events")
+	}
+	aggregate3 := contractAbi.Methods["aggregate3"]
+	if aggregate3.Sig != "aggregate3((address,bool,bytes)[])" || !aggregate3.IsPayable() {
+		t.Errorf("Unexpected aggregate3: %s", aggregate3.Sig)
+	}
+	if contractAbi.Methods["balanceOf"].StateMutability != "view" || !contractAbi.HasReceive() || len(contractAbi.Constructor.Inputs) != 2 {
+		t.Error("Unexpected modifiers")
+	}
+
+	expected := []string{
+		"constructor(string name, string symbol)",
+		"function aggregate3(tuple(address target, bool allowFailure, bytes callData)[] calls) payable returns (tuple(bool success, bytes returnData)[] returnData)",
+		"function balanceOf(address owner) view returns (uint256)",
+		"function transfer(address to, uint256 amount) returns (bool)",
+		"event Ping(address indexed sender) anonymous",
+		"event Transfer(address indexed from, address indexed to, uint256 value)",
+		"error InsufficientBalance(uint256 available, uint256 required)",
+		"receive() external payable",
+	}
+	formatted := FormatHumanReadableABI(contractAbi)
+	if !reflect.DeepEqual(formatted, expected) {
+		t.Errorf("FormatHumanReadableABI() = %q", formatted)
+	}
+	if _, err := ParseHumanReadableABI(formatted...); err != nil {
+		t.Errorf("Round trip failed: %v", err)
+	}
+
+	invalid := []string{
+		"function transfer(address to, uint256 amount",
+		"function (address)",
+		"function transfer(address indexed to)",
+		"event Transfer(address from) returns (bool)",
+		"function transfer(address to amount)",
+		"function transfer(uint7)",
+	}
+	for _, fragment := range invalid {
+		if _, err := ParseHumanReadableABI(fragment); !errors.Is(err, ErrInvalidABI) {
+			t.Errorf("Expected ErrInvalidABI for %q, got %v", fragment, err)
+		}
+	}
+}
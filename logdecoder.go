@@ -0,0 +1,242 @@
+package etherkit
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+//############ Log Decoder ############
+
+// DecodedLog 解码后的事件日志
+type DecodedLog struct {
+	Name      string                 // 事件名
+	Signature string                 // 事件签名，如 Transfer(address,address,uint256)
+	Anonymous bool                   // 是否为匿名事件
+	Fields    map[string]interface{} // 参数名到值，未命名的参数为 arg0、arg1...；动态类型的 indexed 参数为 common.Hash
+	Values    []interface{}          // 按事件参数顺序排列的值
+	Log       *types.Log             // 原始日志
+
+	event abi.Event
+}
+
+// LogDecoder 根据一个或多个 ABI 解码事件日志，按 topic0 匹配事件，topic0 无法匹配时尝试匿名事件。
+// 并发安全
+type LogDecoder struct {
+	mu        sync.RWMutex
+	events    map[common.Hash][]abi.Event
+	anonymous []abi.Event
+	seen      map[string]bool
+}
+
+// NewLogDecoder 创建日志解码器
+func NewLogDecoder(abis ...abi.ABI) *LogDecoder {
+	d := &LogDecoder{
+		events: make(map[common.Hash][]abi.Event),
+		seen:   make(map[string]bool),
+	}
+	for _, a := range abis {
+		d.AddABI(a)
+	}
+	return d
+}
+
+// AddABI 添加 ABI 中的全部事件。签名相同但 indexed 参数不同的事件（如 ERC20 与 ERC721 的 Transfer）会同时保留
+func (d *LogDecoder) AddABI(contractAbi abi.ABI) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, event := range contractAbi.Events {
+		key := eventLayoutKey(event)
+		if d.seen[key] {
+			continue
+		}
+		d.seen[key] = true
+		if event.Anonymous {
+			d.anonymous = append(d.anonymous, event)
+		} else {
+			d.events[event.ID] = append(d.events[event.ID], event)
+		}
+	}
+}
+
+// Decode 解码单条日志，没有匹配的事件时返回 ErrEventNotFound
+func (d *LogDecoder) Decode(log *types.Log) (*DecodedLog, error) {
+	if log == nil {
+		return nil, errors.Wrap(ErrEventNotFound, "nil log")
+	}
+
+	d.mu.RLock()
+	var candidates []abi.Event
+	if len(log.Topics) > 0 {
+		candidates = append(candidates, d.events[log.Topics[0]]...)
+	}
+	candidates = append(candidates, d.anonymous...)
+	d.mu.RUnlock()
+
+	for _, event := range candidates {
+		values, err := unpackEventLog(event, log)
+		if err != nil {
+			continue
+		}
+		return newDecodedLog(event, values, log), nil
+	}
+	topic := "no topics"
+	if len(log.Topics) > 0 {
+		topic = log.Topics[0].Hex()
+	}
+	return nil, errors.Wrapf(ErrEventNotFound, "log %d of tx %s (%s)", log.Index, log.TxHash.Hex(), topic)
+}
+
+// DecodeInto 解码日志并写入 out 指向的结构体，字段按 `abi:"name"` 标签或驼峰字段名匹配事件参数
+func (d *LogDecoder) DecodeInto(log *types.Log, out interface{}) error {
+	decoded, err := d.Decode(log)
+	if err != nil {
+		return err
+	}
+	return decoded.Into(out)
+}
+
+// DecodeLogs 解码多条日志，跳过无法识别的日志
+func (d *LogDecoder) DecodeLogs(logs []*types.Log) []*DecodedLog {
+	var decoded []*DecodedLog
+	for _, log := range logs {
+		if l, err := d.Decode(log); err == nil {
+			decoded = append(decoded, l)
+		}
+	}
+	return decoded
+}
+
+// DecodeReceipt 解码交易回执中的所有日志，跳过无法识别的日志
+func (d *LogDecoder) DecodeReceipt(receipt *types.Receipt) []*DecodedLog {
+	if receipt == nil {
+		return nil
+	}
+	return d.DecodeLogs(receipt.Logs)
+}
+
+// Into 将解码结果写入 out 指向的结构体
+func (l *DecodedLog) Into(out interface{}) error {
+	dst := reflect.ValueOf(out)
+	if dst.Kind() != reflect.Ptr || dst.IsNil() {
+		return errors.Wrapf(ErrReturnTypeMismatch, "%s: expected non-nil pointer, got %T", l.Name, out)
+	}
+	dst = derefAlloc(dst.Elem())
+	if dst.Kind() != reflect.Struct {
+		return errors.Wrapf(ErrReturnTypeMismatch, "%s: cannot decode event into %s", l.Name, dst.Type())
+	}
+	for i, input := range l.event.Inputs {
+		field, err := findABIField(dst, input.Name, i)
+		if err != nil {
+			return errors.Wrapf(err, "%s", l.Name)
+		}
+		typ := input.Type
+		if input.Indexed && isHashedTopicType(typ) {
+			typ = abi.Type{T: abi.FixedBytesTy, Size: 32}
+		}
+		if err := assignABIValue(field, reflect.ValueOf(l.Values[i]), typ, l.Name+"."+eventFieldName(input.Name, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unpackEventLog 按事件解码日志，返回按参数顺序排列的值；日志与事件不匹配时返回 ErrEventMismatch
+func unpackEventLog(event abi.Event, log *types.Log) ([]interface{}, error) {
+	topics := log.Topics
+	if !event.Anonymous {
+		if len(topics) == 0 || topics[0] != event.ID {
+			return nil, errors.Wrapf(ErrEventMismatch, "%s at log %d of tx %s", event.Name, log.Index, log.TxHash.Hex())
+		}
+		topics = topics[1:]
+	}
+
+	var indexed abi.Arguments
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if len(indexed) != len(topics) {
+		return nil, errors.Wrapf(ErrEventMismatch, "%s expects %d indexed topics, got %d", event.Name, len(indexed), len(topics))
+	}
+
+	nonIndexed, err := event.Inputs.Unpack(log.Data)
+	if err != nil {
+		return nil, errors.Wrapf(ErrEventMismatch, "%s: %v", event.Name, err)
+	}
+
+	values := make([]interface{}, 0, len(event.Inputs))
+	for _, arg := range event.Inputs {
+		if !arg.Indexed {
+			values = append(values, nonIndexed[0])
+			nonIndexed = nonIndexed[1:]
+			continue
+		}
+		// 动态类型（string、bytes、数组、tuple）的 indexed 参数只保存了 keccak256 哈希
+		if isHashedTopicType(arg.Type) {
+			values = append(values, topics[0])
+		} else {
+			field := map[string]interface{}{}
+			arg.Name = "value"
+			if err := abi.ParseTopicsIntoMap(field, abi.Arguments{arg}, topics[:1]); err != nil {
+				return nil, errors.Wrapf(ErrEventMismatch, "%s: %v", event.Name, err)
+			}
+			values = append(values, field["value"])
+		}
+		topics = topics[1:]
+	}
+	return values, nil
+}
+
+func newDecodedLog(event abi.Event, values []interface{}, log *types.Log) *DecodedLog {
+	fields := make(map[string]interface{}, len(values))
+	for i, input := range event.Inputs {
+		fields[eventFieldName(input.Name, i)] = values[i]
+	}
+	return &DecodedLog{
+		Name:      event.Name,
+		Signature: event.Sig,
+		Anonymous: event.Anonymous,
+		Fields:    fields,
+		Values:    values,
+		Log:       log,
+		event:     event,
+	}
+}
+
+func isHashedTopicType(typ abi.Type) bool {
+	switch typ.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return true
+	}
+	return false
+}
+
+// eventLayoutKey 事件签名加 indexed 标记，用于区分同签名不同 indexed 布局的事件
+func eventLayoutKey(event abi.Event) string {
+	key := event.Sig
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			key += "1"
+		} else {
+			key += "0"
+		}
+	}
+	if event.Anonymous {
+		key += "anonymous"
+	}
+	return key
+}
+
+func eventFieldName(name string, index int) string {
+	if name == "" {
+		return fmt.Sprintf("arg%d", index)
+	}
+	return name
+}
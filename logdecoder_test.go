@@ -0,0 +1,96 @@
+package etherkit
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	logDecoderERC20ABI = `[
+		{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+	]`
+	logDecoderERC721ABI = `[
+		{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"tokenId","type":"uint256","indexed":true}]},
+		{"type":"event","name":"NameRegistered","inputs":[{"name":"name","type":"string","indexed":true},{"name":"owner","type":"address","indexed":true},{"name":"label","type":"string","indexed":false}]},
+		{"type":"event","name":"Ping","anonymous":true,"inputs":[{"name":"sender","type":"address","indexed":true},{"name":"","type":"uint64","indexed":false}]}
+	]`
+)
+
+func TestLogDecoder(t *testing.T) {
+	mustABI := func(s string) abi.ABI {
+		a, err := GetABI(s)
+		if err != nil {
+			t.Fatalf("GetABI() failed: %v", err)
+		}
+		return a
+	}
+	decoder := NewLogDecoder(mustABI(logDecoderERC20ABI), mustABI(logDecoderERC721ABI))
+	decoder.AddABI(mustABI(logDecoderERC20ABI))
+
+	transferTopic := common.HexToHash(ERC20TransferEventTopic)
+	from, to := common.BytesToHash(guardTrusted.Bytes()), common.BytesToHash(guardUnrelated.Bytes())
+
+	erc20Log := &types.Log{Topics: []common.Hash{transferTopic, from, to}, Data: common.BigToHash(big.NewInt(5)).Bytes()}
+	erc721Log := &types.Log{Topics: []common.Hash{transferTopic, from, to, common.BigToHash(big.NewInt(9))}}
+
+	decoded, err := decoder.Decode(erc20Log)
+	if err != nil {
+		t.Fatalf("Decode(erc20) failed: %v", err)
+	}
+	if decoded.Signature != "Transfer(address,address,uint256)" || decoded.Fields["to"] != guardUnrelated || decoded.Fields["value"].(*big.Int).Int64() != 5 {
+		t.Errorf("Unexpected ERC20 transfer: %+v", decoded)
+	}
+	decoded, err = decoder.Decode(erc721Log)
+	if err != nil || decoded.Fields["tokenId"].(*big.Int).Int64() != 9 {
+		t.Errorf("Decode(erc721) = %+v, %v", decoded, err)
+	}
+
+	// indexed string 只能得到哈希
+	labelData, err := abi.Arguments{{Type: mustNewType("string")}}.Pack("vitalik")
+	if err != nil {
+		t.Fatalf("Pack() failed: %v", err)
+	}
+	nameTopic := crypto.Keccak256Hash([]byte("vitalik"))
+	registered := &types.Log{
+		Topics: []common.Hash{crypto.Keccak256Hash([]byte("NameRegistered(string,address,string)")), nameTopic, from},
+		Data:   labelData,
+	}
+	var event struct {
+		Name  common.Hash
+		Owner common.Address
+		Label string
+	}
+	if err := decoder.DecodeInto(registered, &event); err != nil {
+		t.Fatalf("DecodeInto() failed: %v", err)
+	}
+	if event.Name != nameTopic || event.Owner != guardTrusted || event.Label != "vitalik" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+	var wrong struct{ Name string }
+	if err := decoder.DecodeInto(registered, &wrong); !errors.Is(err, ErrReturnTypeMismatch) {
+		t.Errorf("Expected ErrReturnTypeMismatch, got %v", err)
+	}
+
+	// 匿名事件没有 topic0
+	ping := &types.Log{Topics: []common.Hash{from}, Data: common.BigToHash(big.NewInt(3)).Bytes()}
+	decoded, err = decoder.Decode(ping)
+	if err != nil || !decoded.Anonymous || decoded.Name != "Ping" || decoded.Fields["arg1"] != uint64(3) {
+		t.Errorf("Decode(anonymous) = %+v, %v", decoded, err)
+	}
+
+	unknown := &types.Log{Topics: []common.Hash{common.HexToHash("0x01"), from, to}}
+	if _, err := decoder.Decode(unknown); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("Expected ErrEventNotFound, got %v", err)
+	}
+
+	receipt := &types.Receipt{Logs: []*types.Log{erc20Log, unknown, erc721Log, registered}}
+	if logs := decoder.DecodeReceipt(receipt); len(logs) != 3 || logs[2].Name != "NameRegistered" {
+		t.Errorf("DecodeReceipt() = %d logs", len(logs))
+	}
+}
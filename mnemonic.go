@@ -0,0 +1,211 @@
+package etherkit
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tyler-smith/go-bip39/wordlists"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+//############ Mnemonic (BIP39) ############
+
+// MnemonicLanguage 助记词的语言（BIP39 词表）
+type MnemonicLanguage string
+
+const (
+	MnemonicEnglish            MnemonicLanguage = "english"
+	MnemonicChineseSimplified  MnemonicLanguage = "chinese_simplified"
+	MnemonicChineseTraditional MnemonicLanguage = "chinese_traditional"
+	MnemonicCzech              MnemonicLanguage = "czech"
+	MnemonicFrench             MnemonicLanguage = "french"
+	MnemonicItalian            MnemonicLanguage = "italian"
+	MnemonicJapanese           MnemonicLanguage = "japanese"
+	MnemonicKorean             MnemonicLanguage = "korean"
+	MnemonicSpanish            MnemonicLanguage = "spanish"
+)
+
+// 助记词支持的单词数量
+const (
+	Mnemonic12Words = 12
+	Mnemonic15Words = 15
+	Mnemonic18Words = 18
+	Mnemonic21Words = 21
+	Mnemonic24Words = 24
+)
+
+// mnemonicLanguages 语言检测的顺序
+var mnemonicLanguages = []MnemonicLanguage{
+	MnemonicEnglish,
+	MnemonicChineseSimplified,
+	MnemonicChineseTraditional,
+	MnemonicCzech,
+	MnemonicFrench,
+	MnemonicItalian,
+	MnemonicJapanese,
+	MnemonicKorean,
+	MnemonicSpanish,
+}
+
+var mnemonicWordlists = map[MnemonicLanguage][]string{
+	MnemonicEnglish:            wordlists.English,
+	MnemonicChineseSimplified:  wordlists.ChineseSimplified,
+	MnemonicChineseTraditional: wordlists.ChineseTraditional,
+	MnemonicCzech:              wordlists.Czech,
+	MnemonicFrench:             wordlists.French,
+	MnemonicItalian:            wordlists.Italian,
+	MnemonicJapanese:           wordlists.Japanese,
+	MnemonicKorean:             wordlists.Korean,
+	MnemonicSpanish:            wordlists.Spanish,
+}
+
+// mnemonicWordIndexes 每种语言 NFKD 规范化后的单词到序号的映射
+var mnemonicWordIndexes = func() map[MnemonicLanguage]map[string]int {
+	indexes := make(map[MnemonicLanguage]map[string]int, len(mnemonicWordlists))
+	for lang, words := range mnemonicWordlists {
+		index := make(map[string]int, len(words))
+		for i, word := range words {
+			index[norm.NFKD.String(word)] = i
+		}
+		indexes[lang] = index
+	}
+	return indexes
+}()
+
+// GenerateMnemonic 生成英文助记词，wordCount 为 12/15/18/21/24
+func GenerateMnemonic(wordCount int) (string, error) {
+	return GenerateMnemonicWithLanguage(wordCount, MnemonicEnglish)
+}
+
+// GenerateMnemonicWithLanguage 使用指定语言的词表生成助记词
+func GenerateMnemonicWithLanguage(wordCount int, lang MnemonicLanguage) (string, error) {
+	if wordCount%3 != 0 || wordCount < Mnemonic12Words || wordCount > Mnemonic24Words {
+		return "", errors.Wrapf(ErrInvalidMnemonic, "unsupported word count %d", wordCount)
+	}
+
+	entropy := make([]byte, wordCount*4/3)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", errors.Wrap(err, "failed to generate entropy")
+	}
+	return EntropyToMnemonic(entropy, lang)
+}
+
+// EntropyToMnemonic 将熵（16/20/24/28/32字节）转换为指定语言的助记词
+func EntropyToMnemonic(entropy []byte, lang MnemonicLanguage) (string, error) {
+	words, ok := mnemonicWordlists[lang]
+	if !ok {
+		return "", errors.Wrapf(ErrInvalidMnemonic, "unsupported language %q", lang)
+	}
+	entropyBits := len(entropy) * 8
+	if entropyBits%32 != 0 || entropyBits < 128 || entropyBits > 256 {
+		return "", errors.Wrapf(ErrInvalidMnemonic, "invalid entropy length %d", len(entropy))
+	}
+
+	// entropy ‖ checksum，每11位对应一个单词
+	checksumBits := entropyBits / 32
+	wordCount := (entropyBits + checksumBits) / 11
+	hash := sha256.Sum256(entropy)
+	data := new(big.Int).SetBytes(entropy)
+	data.Lsh(data, uint(checksumBits))
+	data.Or(data, big.NewInt(int64(hash[0]>>(8-checksumBits))))
+
+	mask := big.NewInt(2047)
+	result := make([]string, wordCount)
+	for i := wordCount - 1; i >= 0; i-- {
+		result[i] = words[new(big.Int).And(data, mask).Int64()]
+		data.Rsh(data, 11)
+	}
+
+	separator := " "
+	if lang == MnemonicJapanese {
+		separator = "\u3000"
+	}
+	return strings.Join(result, separator), nil
+}
+
+// MnemonicToEntropy 校验助记词并还原熵，同时返回识别出的语言
+func MnemonicToEntropy(mnemonic string) ([]byte, MnemonicLanguage, error) {
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	if len(words)%3 != 0 || len(words) < Mnemonic12Words || len(words) > Mnemonic24Words {
+		return nil, "", errors.Wrapf(ErrInvalidMnemonic, "invalid word count %d", len(words))
+	}
+
+	var checksumErr error
+	for _, lang := range mnemonicLanguages {
+		entropy, err := mnemonicWordsToEntropy(words, mnemonicWordIndexes[lang])
+		if err == nil {
+			return entropy, lang, nil
+		}
+		if err == errMnemonicChecksum && checksumErr == nil {
+			checksumErr = errors.Wrapf(ErrInvalidMnemonic, "checksum mismatch (%s)", lang)
+		}
+	}
+	if checksumErr != nil {
+		return nil, "", checksumErr
+	}
+	return nil, "", errors.Wrap(ErrInvalidMnemonic, "words not found in any wordlist")
+}
+
+// ValidateMnemonic 校验助记词的单词和校验和，无效时返回 ErrInvalidMnemonic
+func ValidateMnemonic(mnemonic string) error {
+	_, _, err := MnemonicToEntropy(mnemonic)
+	return err
+}
+
+// IsValidMnemonic 助记词是否有效
+func IsValidMnemonic(mnemonic string) bool {
+	return ValidateMnemonic(mnemonic) == nil
+}
+
+// DetectMnemonicLanguage 识别助记词使用的语言
+func DetectMnemonicLanguage(mnemonic string) (MnemonicLanguage, error) {
+	_, lang, err := MnemonicToEntropy(mnemonic)
+	return lang, err
+}
+
+// MnemonicToSeed 校验助记词并使用 BIP39 passphrase 生成64字节种子
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	sentence := strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " ")
+	salt := norm.NFKD.String("mnemonic" + passphrase)
+	return pbkdf2.Key([]byte(sentence), []byte(salt), 2048, 64, sha512.New), nil
+}
+
+var (
+	errMnemonicUnknownWord = errors.New("unknown mnemonic word")
+	errMnemonicChecksum    = errors.New("mnemonic checksum mismatch")
+)
+
+// mnemonicWordsToEntropy 使用词表还原熵并校验 checksum
+func mnemonicWordsToEntropy(words []string, index map[string]int) ([]byte, error) {
+	data := new(big.Int)
+	for _, word := range words {
+		i, ok := index[word]
+		if !ok {
+			return nil, errMnemonicUnknownWord
+		}
+		data.Lsh(data, 11)
+		data.Or(data, big.NewInt(int64(i)))
+	}
+
+	checksumBits := len(words) / 3
+	entropyBytes := (len(words)*11 - checksumBits) / 8
+	checksum := new(big.Int).And(data, big.NewInt(int64(1<<checksumBits)-1))
+	data.Rsh(data, uint(checksumBits))
+
+	entropy := make([]byte, entropyBytes)
+	data.FillBytes(entropy)
+
+	hash := sha256.Sum256(entropy)
+	if checksum.Int64() != int64(hash[0]>>(8-checksumBits)) {
+		return nil, errMnemonicChecksum
+	}
+	return entropy, nil
+}
@@ -0,0 +1,164 @@
+package etherkit
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEntropyToMnemonic(t *testing.T) {
+	// BIP39 官方测试向量（passphrase 为 TREZOR）
+	tests := []struct {
+		name     string
+		entropy  string
+		lang     MnemonicLanguage
+		mnemonic string
+		seed     string
+	}{
+		{
+			name:     "English 128 bits zero",
+			entropy:  "00000000000000000000000000000000",
+			lang:     MnemonicEnglish,
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			name:     "English 128 bits",
+			entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			lang:     MnemonicEnglish,
+			mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+			seed:     "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			name:     "English 256 bits",
+			entropy:  "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			lang:     MnemonicEnglish,
+			mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+			seed:     "dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entropy, _ := hex.DecodeString(tt.entropy)
+			mnemonic, err := EntropyToMnemonic(entropy, tt.lang)
+			if err != nil {
+				t.Fatalf("EntropyToMnemonic() failed: %v", err)
+			}
+			if mnemonic != tt.mnemonic {
+				t.Errorf("mnemonic = %s, expected %s", mnemonic, tt.mnemonic)
+			}
+
+			restored, lang, err := MnemonicToEntropy(mnemonic)
+			if err != nil {
+				t.Fatalf("MnemonicToEntropy() failed: %v", err)
+			}
+			if hex.EncodeToString(restored) != tt.entropy || lang != tt.lang {
+				t.Errorf("entropy = %x (%s), expected %s (%s)", restored, lang, tt.entropy, tt.lang)
+			}
+
+			seed, err := MnemonicToSeed(mnemonic, "TREZOR")
+			if err != nil {
+				t.Fatalf("MnemonicToSeed() failed: %v", err)
+			}
+			if hex.EncodeToString(seed) != tt.seed {
+				t.Errorf("seed = %x, expected %s", seed, tt.seed)
+			}
+		})
+	}
+}
+
+func TestGenerateMnemonic(t *testing.T) {
+	for _, count := range []int{Mnemonic12Words, Mnemonic15Words, Mnemonic18Words, Mnemonic21Words, Mnemonic24Words} {
+		mnemonic, err := GenerateMnemonic(count)
+		if err != nil {
+			t.Fatalf("GenerateMnemonic(%d) failed: %v", count, err)
+		}
+		if n := len(strings.Fields(mnemonic)); n != count {
+			t.Errorf("GenerateMnemonic(%d) generated %d words", count, n)
+		}
+		if err := ValidateMnemonic(mnemonic); err != nil {
+			t.Errorf("Generated mnemonic is invalid: %v", err)
+		}
+	}
+
+	for _, count := range []int{0, 11, 13, 27} {
+		if _, err := GenerateMnemonic(count); !errors.Is(err, ErrInvalidMnemonic) {
+			t.Errorf("GenerateMnemonic(%d) expected ErrInvalidMnemonic, got %v", count, err)
+		}
+	}
+}
+
+func TestGenerateMnemonicWithLanguage(t *testing.T) {
+	for _, lang := range mnemonicLanguages {
+		t.Run(string(lang), func(t *testing.T) {
+			mnemonic, err := GenerateMnemonicWithLanguage(Mnemonic24Words, lang)
+			if err != nil {
+				t.Fatalf("GenerateMnemonicWithLanguage() failed: %v", err)
+			}
+			if !IsValidMnemonic(mnemonic) {
+				t.Errorf("Generated %s mnemonic is invalid", lang)
+			}
+			if _, err := MnemonicToSeed(mnemonic, "passphrase"); err != nil {
+				t.Errorf("MnemonicToSeed() failed: %v", err)
+			}
+		})
+	}
+
+	if _, err := GenerateMnemonicWithLanguage(Mnemonic12Words, "klingon"); !errors.Is(err, ErrInvalidMnemonic) {
+		t.Errorf("Expected ErrInvalidMnemonic, got %v", err)
+	}
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+		valid    bool
+	}{
+		{"Valid", "test test test test test test test test test test test junk", true},
+		{"Extra whitespace", "  test test test test test test test test test test test   junk ", true},
+		{"Bad checksum", "test test test test test test test test test test test test", false},
+		{"Unknown word", "test test test test test test test test test test test etherkit", false},
+		{"Wrong word count", "test test test test test test test test test test junk", false},
+		{"Empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMnemonic(tt.mnemonic)
+			if tt.valid && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidMnemonic) {
+				t.Errorf("Expected ErrInvalidMnemonic, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBuildPrivateKeyFromMnemonicWithPassphrase(t *testing.T) {
+	testMnemonic := "test test test test test test test test test test test junk"
+
+	pk, err := BuildPrivateKeyFromMnemonicWithPassphrase(testMnemonic, "", 0)
+	if err != nil {
+		t.Fatalf("BuildPrivateKeyFromMnemonicWithPassphrase() failed: %v", err)
+	}
+	if address := PrivateKeyToAddress(pk).Hex(); address != "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266" {
+		t.Errorf("address = %s, expected 0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", address)
+	}
+
+	// passphrase 不同，地址不同
+	pk2, err := BuildPrivateKeyFromMnemonicWithPassphrase(testMnemonic, "secret", 0)
+	if err != nil {
+		t.Fatalf("BuildPrivateKeyFromMnemonicWithPassphrase() failed: %v", err)
+	}
+	if PrivateKeyToAddress(pk) == PrivateKeyToAddress(pk2) {
+		t.Error("Different passphrases should generate different addresses")
+	}
+
+	if _, err := BuildPrivateKeyFromMnemonicWithPassphrase("invalid mnemonic", "", 0); !errors.Is(err, ErrInvalidMnemonic) {
+		t.Errorf("Expected ErrInvalidMnemonic, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package etherkit
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+//############ Public Key ############
+
+// 公钥的字节长度
+const (
+	CompressedPublicKeyLength   = 33 // 0x02/0x03 ‖ X
+	UncompressedPublicKeyLength = 65 // 0x04 ‖ X ‖ Y
+	RawPublicKeyLength          = 64 // X ‖ Y
+)
+
+// ParsePublicKey 解析 secp256k1 公钥并校验其在曲线上。支持的输入：
+//   - *ecdsa.PublicKey、ecdsa.PublicKey
+//   - []byte 或十六进制字符串（可带0x），格式为33字节压缩公钥、65字节带04前缀的公钥或64字节不带前缀的公钥
+func ParsePublicKey(publicKey interface{}) (*ecdsa.PublicKey, error) {
+	var data []byte
+	switch v := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if v == nil {
+			return nil, errors.Wrap(ErrInvalidPublicKey, "nil public key")
+		}
+		return validatePublicKey(v)
+	case ecdsa.PublicKey:
+		return validatePublicKey(&v)
+	case []byte:
+		data = v
+	case string:
+		s := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(v), "0x"), "0X")
+		decoded, err := hexutil.Decode("0x" + s)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidPublicKey, "invalid hex: %v", err)
+		}
+		data = decoded
+	default:
+		return nil, errors.Wrapf(ErrInvalidPublicKey, "unsupported type %T", publicKey)
+	}
+
+	switch len(data) {
+	case CompressedPublicKeyLength:
+		if data[0] != 0x02 && data[0] != 0x03 {
+			return nil, errors.Wrapf(ErrInvalidPublicKey, "invalid compressed prefix %#x", data[0])
+		}
+		pub, err := crypto.DecompressPubkey(data)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidPublicKey, err.Error())
+		}
+		return pub, nil
+	case RawPublicKeyLength:
+		data = append([]byte{0x04}, data...)
+	case UncompressedPublicKeyLength:
+		if data[0] != 0x04 {
+			return nil, errors.Wrapf(ErrInvalidPublicKey, "invalid uncompressed prefix %#x", data[0])
+		}
+	default:
+		return nil, errors.Wrapf(ErrInvalidPublicKey, "invalid length %d", len(data))
+	}
+
+	// UnmarshalPubkey 会校验点在曲线上
+	pub, err := crypto.UnmarshalPubkey(data)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidPublicKey, err.Error())
+	}
+	return pub, nil
+}
+
+// IsValidPublicKey 是否是曲线上的有效公钥
+func IsValidPublicKey(publicKey interface{}) bool {
+	_, err := ParsePublicKey(publicKey)
+	return err == nil
+}
+
+// PublicKeyToAddress 公钥到地址，公钥格式错误或不在曲线上时返回 ErrInvalidPublicKey
+func PublicKeyToAddress(publicKey interface{}) (common.Address, error) {
+	pub, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// CompressPublicKey 将公钥转换为33字节的压缩格式
+func CompressPublicKey(publicKey interface{}) ([]byte, error) {
+	pub, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.CompressPubkey(pub), nil
+}
+
+// DecompressPublicKey 将公钥转换为65字节带04前缀的非压缩格式
+func DecompressPublicKey(publicKey interface{}) ([]byte, error) {
+	pub, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.FromECDSAPub(pub), nil
+}
+
+// RecoverPublicKey 从32字节摘要和签名中恢复公钥，签名会先经过 NormalizeSignature 规范化
+func RecoverPublicKey(hash, signature []byte) (*ecdsa.PublicKey, error) {
+	if len(hash) != common.HashLength {
+		return nil, errors.Wrapf(ErrInvalidSignature, "hash length %d", len(hash))
+	}
+
+	sig, err := NormalizeSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidSignature, err.Error())
+	}
+	return publicKey, nil
+}
+
+// RecoverPublicKeyFromMessage 从 EIP-191 personal_sign 签名中恢复公钥
+func RecoverPublicKeyFromMessage(message, signature []byte) (*ecdsa.PublicKey, error) {
+	return RecoverPublicKey(HashPersonalMessage(message).Bytes(), signature)
+}
+
+// RecoverPublicKeyFromTx 从已签名的交易中恢复发送者公钥，支持 legacy（含 EIP-155）和类型化交易
+func RecoverPublicKeyFromTx(tx *types.Transaction) (*ecdsa.PublicKey, error) {
+	v, r, s := tx.RawSignatureValues()
+	if v == nil || r == nil || s == nil || (r.Sign() == 0 && s.Sign() == 0) {
+		return nil, errors.Wrap(ErrInvalidSignature, "transaction is not signed")
+	}
+	if r.BitLen() > 256 || s.BitLen() > 256 {
+		return nil, errors.Wrap(ErrInvalidSignature, "signature values out of range")
+	}
+
+	var signer types.Signer
+	recoveryID := new(big.Int).Set(v)
+	switch {
+	case tx.Type() != types.LegacyTxType:
+		signer = types.LatestSignerForChainID(tx.ChainId())
+	case tx.Protected():
+		// EIP-155: v = chainId*2 + 35 + recoveryID
+		signer = types.NewEIP155Signer(tx.ChainId())
+		recoveryID.Sub(recoveryID, new(big.Int).Add(new(big.Int).Lsh(tx.ChainId(), 1), big.NewInt(35)))
+	default:
+		signer = types.HomesteadSigner{}
+		recoveryID.Sub(recoveryID, big.NewInt(27))
+	}
+	if !recoveryID.IsUint64() || recoveryID.Uint64() > 1 {
+		return nil, errors.Wrapf(ErrInvalidSignature, "invalid recovery id %s", recoveryID)
+	}
+
+	sig := make([]byte, crypto.SignatureLength)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	sig[crypto.RecoveryIDOffset] = byte(recoveryID.Uint64())
+	return RecoverPublicKey(signer.Hash(tx).Bytes(), sig)
+}
+
+// validatePublicKey 校验公钥在 secp256k1 曲线上
+func validatePublicKey(pub *ecdsa.PublicKey) (*ecdsa.PublicKey, error) {
+	if pub.X == nil || pub.Y == nil || !crypto.S256().IsOnCurve(pub.X, pub.Y) {
+		return nil, errors.Wrap(ErrInvalidPublicKey, "point is not on secp256k1 curve")
+	}
+	return pub, nil
+}
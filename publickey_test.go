@@ -0,0 +1,137 @@
+package etherkit
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestParsePublicKey(t *testing.T) {
+	pk, err := BuildPrivateKeyFromHex(signerTestHexPk)
+	if err != nil {
+		t.Fatalf("BuildPrivateKeyFromHex() failed: %v", err)
+	}
+	expected := PrivateKeyToAddress(pk)
+	uncompressed := crypto.FromECDSAPub(&pk.PublicKey)
+	compressed := crypto.CompressPubkey(&pk.PublicKey)
+
+	inputs := map[string]interface{}{
+		"*ecdsa.PublicKey":     &pk.PublicKey,
+		"ecdsa.PublicKey":      pk.PublicKey,
+		"uncompressed bytes":   uncompressed,
+		"raw bytes":            uncompressed[1:],
+		"compressed bytes":     compressed,
+		"uncompressed 0x hex":  "0x" + hex.EncodeToString(uncompressed),
+		"raw hex":              hex.EncodeToString(uncompressed[1:]),
+		"compressed hex":       hex.EncodeToString(compressed),
+		"GetHexPublicKey form": GetHexPublicKey(pk),
+	}
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			address, err := PublicKeyToAddress(input)
+			if err != nil {
+				t.Fatalf("PublicKeyToAddress() failed: %v", err)
+			}
+			if address != expected {
+				t.Errorf("address = %s, expected %s", address.Hex(), expected.Hex())
+			}
+		})
+	}
+	for _, input := range [][]byte{uncompressed, uncompressed[1:], compressed} {
+		if PublicKeyBytesToAddress(input) != expected {
+			t.Errorf("PublicKeyBytesToAddress(%x) mismatch", input)
+		}
+	}
+
+	// 压缩与解压互相转换
+	c, err := CompressPublicKey(uncompressed)
+	if err != nil || !bytes.Equal(c, compressed) {
+		t.Errorf("CompressPublicKey() = %x, %v", c, err)
+	}
+	u, err := DecompressPublicKey(compressed)
+	if err != nil || !bytes.Equal(u, uncompressed) {
+		t.Errorf("DecompressPublicKey() = %x, %v", u, err)
+	}
+
+	// 不在曲线上的点
+	offCurve := append([]byte{}, uncompressed...)
+	offCurve[64] ^= 0x01
+	badPrefix := append([]byte{0x05}, compressed[1:]...)
+	for name, input := range map[string]interface{}{
+		"off curve":   offCurve,
+		"bad prefix":  badPrefix,
+		"short":       compressed[:20],
+		"not hex":     "0xzz",
+		"wrong type":  123,
+		"zero point":  make([]byte, 64),
+		"compressed0": append([]byte{0x02}, make([]byte, 32)...),
+	} {
+		if _, err := ParsePublicKey(input); !errors.Is(err, ErrInvalidPublicKey) {
+			t.Errorf("%s: expected ErrInvalidPublicKey, got %v", name, err)
+		}
+		if IsValidPublicKey(input) {
+			t.Errorf("%s: IsValidPublicKey() = true", name)
+		}
+	}
+
+	// 格式错误的公钥不再静默得到错误的地址
+	if address := PublicKeyBytesToAddress(compressed[:20]); address != (common.Address{}) {
+		t.Errorf("PublicKeyBytesToAddress() = %s, expected zero address", address.Hex())
+	}
+}
+
+func TestRecoverPublicKey(t *testing.T) {
+	pk, err := BuildPrivateKeyFromHex(signerTestHexPk)
+	if err != nil {
+		t.Fatalf("BuildPrivateKeyFromHex() failed: %v", err)
+	}
+
+	signature, err := SignPersonalMessage(pk, []byte("hello"))
+	if err != nil {
+		t.Fatalf("SignPersonalMessage() failed: %v", err)
+	}
+	pub, err := RecoverPublicKeyFromMessage([]byte("hello"), signature)
+	if err != nil {
+		t.Fatalf("RecoverPublicKeyFromMessage() failed: %v", err)
+	}
+	if !pub.Equal(&pk.PublicKey) {
+		t.Error("Recovered wrong public key from message")
+	}
+
+	to := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	chainID := big.NewInt(MainnetChainID)
+	txs := map[string]struct {
+		tx     *types.Transaction
+		signer types.Signer
+	}{
+		"legacy homestead": {types.NewTx(&types.LegacyTx{Nonce: 1, To: &to, Gas: 21000, GasPrice: big.NewInt(1)}), types.HomesteadSigner{}},
+		"legacy EIP-155":   {types.NewTx(&types.LegacyTx{Nonce: 1, To: &to, Gas: 21000, GasPrice: big.NewInt(1)}), types.NewEIP155Signer(chainID)},
+		"dynamic fee":      {types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 1, To: &to, Gas: 21000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2)}), types.NewLondonSigner(chainID)},
+	}
+	for name, tt := range txs {
+		t.Run(name, func(t *testing.T) {
+			signed, err := types.SignTx(tt.tx, tt.signer, pk)
+			if err != nil {
+				t.Fatalf("SignTx() failed: %v", err)
+			}
+			pub, err := RecoverPublicKeyFromTx(signed)
+			if err != nil {
+				t.Fatalf("RecoverPublicKeyFromTx() failed: %v", err)
+			}
+			if !pub.Equal(&pk.PublicKey) {
+				t.Error("Recovered wrong public key from transaction")
+			}
+		})
+	}
+
+	unsigned := types.NewTx(&types.LegacyTx{Nonce: 1, To: &to, Gas: 21000, GasPrice: big.NewInt(1)})
+	if _, err := RecoverPublicKeyFromTx(unsigned); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+}
@@ -0,0 +1,367 @@
+package etherkit
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+//############ Shamir Secret Sharing ############
+
+// 分片的编码格式（十六进制字符串，不以0x开头）：
+//
+//	version(1) ‖ id(4) ‖ threshold(1) ‖ x(1) ‖ kind(1) ‖ language(1) ‖ payload(n) ‖ checksum(4)
+//
+//   - version:   格式版本，当前为 ShareVersion
+//   - id:        一次拆分的随机标识，同一次拆分的分片相同
+//   - threshold: 恢复所需的最少分片数
+//   - x:         分片在 GF(256) 多项式上的横坐标，1..255
+//   - kind:      秘密类型，ShareKindPrivateKey 或 ShareKindMnemonic
+//   - language:  助记词语言在 mnemonicLanguages 中的序号，私钥为0
+//   - payload:   秘密 ‖ sha256(秘密)[:4] 按字节在 GF(256) 上拆分后的 y 值
+//   - checksum:  sha256(前面所有字节)[:4]，用于发现抄写错误
+//
+// 助记词拆分的是 BIP39 熵而不是单词本身，恢复时按原语言重新生成助记词。
+
+// ShareVersion 分片格式版本
+const ShareVersion = 1
+
+// ShareKind 分片中秘密的类型
+type ShareKind byte
+
+const (
+	ShareKindPrivateKey ShareKind = 1
+	ShareKindMnemonic   ShareKind = 2
+)
+
+// MaxShares 最多可以拆分的分片数
+const MaxShares = 255
+
+const (
+	shareHeaderLength   = 9
+	shareChecksumLength = 4
+)
+
+// SecretShare 解析后的秘密分片
+type SecretShare struct {
+	Version   byte
+	ID        [4]byte
+	Threshold byte
+	X         byte
+	Kind      ShareKind
+	Language  MnemonicLanguage
+	Payload   []byte
+}
+
+// String 编码为十六进制分片字符串
+func (s *SecretShare) String() string {
+	data := make([]byte, 0, shareHeaderLength+len(s.Payload)+shareChecksumLength)
+	data = append(data, s.Version)
+	data = append(data, s.ID[:]...)
+	data = append(data, s.Threshold, s.X, byte(s.Kind), mnemonicLanguageIndex(s.Language))
+	data = append(data, s.Payload...)
+	checksum := sha256.Sum256(data)
+	data = append(data, checksum[:shareChecksumLength]...)
+	return hex.EncodeToString(data)
+}
+
+// ParseSecretShare 解析并校验分片字符串
+func ParseSecretShare(share string) (*SecretShare, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(share), "0x"))
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidShare, "share is not valid hex")
+	}
+	if len(data) <= shareHeaderLength+shareChecksumLength {
+		return nil, errors.Wrapf(ErrInvalidShare, "share too short (%d bytes)", len(data))
+	}
+
+	body, checksum := data[:len(data)-shareChecksumLength], data[len(data)-shareChecksumLength:]
+	expected := sha256.Sum256(body)
+	if !bytes.Equal(checksum, expected[:shareChecksumLength]) {
+		return nil, errors.Wrap(ErrInvalidShare, "checksum mismatch")
+	}
+
+	s := &SecretShare{
+		Version:   body[0],
+		Threshold: body[5],
+		X:         body[6],
+		Kind:      ShareKind(body[7]),
+		Payload:   body[shareHeaderLength:],
+	}
+	copy(s.ID[:], body[1:5])
+
+	if s.Version != ShareVersion {
+		return nil, errors.Wrapf(ErrInvalidShare, "unsupported version %d", s.Version)
+	}
+	if s.Threshold < 2 || s.X == 0 {
+		return nil, errors.Wrapf(ErrInvalidShare, "invalid threshold %d or index %d", s.Threshold, s.X)
+	}
+	switch s.Kind {
+	case ShareKindPrivateKey:
+		if body[8] != 0 || len(s.Payload) != 32+shareChecksumLength {
+			return nil, errors.Wrap(ErrInvalidShare, "invalid private key share")
+		}
+	case ShareKindMnemonic:
+		if int(body[8]) >= len(mnemonicLanguages) {
+			return nil, errors.Wrapf(ErrInvalidShare, "unknown mnemonic language %d", body[8])
+		}
+		s.Language = mnemonicLanguages[body[8]]
+		entropyLength := len(s.Payload) - shareChecksumLength
+		if entropyLength%4 != 0 || entropyLength < 16 || entropyLength > 32 {
+			return nil, errors.Wrap(ErrInvalidShare, "invalid mnemonic share")
+		}
+	default:
+		return nil, errors.Wrapf(ErrInvalidShare, "unknown share kind %d", s.Kind)
+	}
+	return s, nil
+}
+
+// ValidateShare 校验分片的格式和校验和
+func ValidateShare(share string) error {
+	_, err := ParseSecretShare(share)
+	return err
+}
+
+// SplitPrivateKey 将私钥拆分为 shares 个分片，任意 threshold 个分片即可恢复
+func SplitPrivateKey(privateKey *ecdsa.PrivateKey, threshold, shares int) ([]string, error) {
+	if privateKey == nil || privateKey.D == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	secret := GetPrivateKeyBytes(privateKey)
+	defer ZeroBytes(secret)
+	return splitSecret(secret, ShareKindPrivateKey, "", threshold, shares)
+}
+
+// SplitMnemonic 将助记词拆分为 shares 个分片，任意 threshold 个分片即可恢复
+func SplitMnemonic(mnemonic string, threshold, shares int) ([]string, error) {
+	entropy, lang, err := MnemonicToEntropy(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	defer ZeroBytes(entropy)
+	return splitSecret(entropy, ShareKindMnemonic, lang, threshold, shares)
+}
+
+// CombinePrivateKeyShares 使用私钥分片恢复私钥
+func CombinePrivateKeyShares(shares []string) (*ecdsa.PrivateKey, error) {
+	first, secret, err := combineShares(shares)
+	if err != nil {
+		return nil, err
+	}
+	defer ZeroBytes(secret)
+	if first.Kind != ShareKindPrivateKey {
+		return nil, errors.Wrap(ErrInvalidShare, "shares do not contain a private key")
+	}
+	pk, err := crypto.ToECDSA(secret)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidPrivateKey, err.Error())
+	}
+	return pk, nil
+}
+
+// CombineMnemonicShares 使用助记词分片恢复助记词
+func CombineMnemonicShares(shares []string) (string, error) {
+	first, secret, err := combineShares(shares)
+	if err != nil {
+		return "", err
+	}
+	defer ZeroBytes(secret)
+	if first.Kind != ShareKindMnemonic {
+		return "", errors.Wrap(ErrInvalidShare, "shares do not contain a mnemonic")
+	}
+	return EntropyToMnemonic(secret, first.Language)
+}
+
+// CombineSharesToSigner 使用分片恢复账户。助记词分片使用默认派生路径的第0个账户
+func CombineSharesToSigner(shares []string) (*Signer, error) {
+	if len(shares) == 0 {
+		return nil, ErrInsufficientShares
+	}
+	first, err := ParseSecretShare(shares[0])
+	if err != nil {
+		return nil, err
+	}
+	if first.Kind == ShareKindMnemonic {
+		mnemonic, err := CombineMnemonicShares(shares)
+		if err != nil {
+			return nil, err
+		}
+		return NewSignerFromMnemonic(mnemonic)
+	}
+	pk, err := CombinePrivateKeyShares(shares)
+	if err != nil {
+		return nil, err
+	}
+	return NewSignerFromPrivateKey(pk)
+}
+
+// splitSecret 在 GF(256) 上为秘密的每个字节构造 threshold-1 次随机多项式，x=1..shares 处的值即为分片
+func splitSecret(secret []byte, kind ShareKind, lang MnemonicLanguage, threshold, shares int) ([]string, error) {
+	if threshold < 2 || threshold > shares || shares > MaxShares {
+		return nil, errors.Wrapf(ErrInvalidShare, "invalid threshold %d of %d shares", threshold, shares)
+	}
+
+	var id [4]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, errors.Wrap(err, "failed to generate share id")
+	}
+
+	digest := sha256.Sum256(secret)
+	data := append(append([]byte{}, secret...), digest[:shareChecksumLength]...)
+	defer ZeroBytes(data)
+
+	coefficients := make([]byte, threshold)
+	defer ZeroBytes(coefficients)
+
+	payloads := make([][]byte, shares)
+	for i := range payloads {
+		payloads[i] = make([]byte, len(data))
+	}
+	for i, b := range data {
+		coefficients[0] = b
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, errors.Wrap(err, "failed to generate polynomial")
+		}
+		for j := range payloads {
+			payloads[j][i] = gf256EvalPolynomial(coefficients, byte(j+1))
+		}
+	}
+
+	result := make([]string, shares)
+	for j, payload := range payloads {
+		share := &SecretShare{
+			Version:   ShareVersion,
+			ID:        id,
+			Threshold: byte(threshold),
+			X:         byte(j + 1),
+			Kind:      kind,
+			Language:  lang,
+			Payload:   payload,
+		}
+		result[j] = share.String()
+	}
+	return result, nil
+}
+
+// combineShares 校验分片属于同一次拆分，并用拉格朗日插值还原秘密
+func combineShares(shares []string) (*SecretShare, []byte, error) {
+	var parsed []*SecretShare
+	seen := make(map[byte]*SecretShare, len(shares))
+	for _, raw := range shares {
+		s, err := ParseSecretShare(raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(parsed) > 0 {
+			first := parsed[0]
+			if s.ID != first.ID || s.Threshold != first.Threshold || s.Kind != first.Kind ||
+				s.Language != first.Language || len(s.Payload) != len(first.Payload) {
+				return nil, nil, errors.Wrap(ErrInvalidShare, "shares belong to different secrets")
+			}
+		}
+		if dup, ok := seen[s.X]; ok {
+			if !bytes.Equal(dup.Payload, s.Payload) {
+				return nil, nil, errors.Wrapf(ErrInvalidShare, "conflicting shares with index %d", s.X)
+			}
+			continue
+		}
+		seen[s.X] = s
+		parsed = append(parsed, s)
+	}
+
+	if len(parsed) == 0 {
+		return nil, nil, ErrInsufficientShares
+	}
+	threshold := int(parsed[0].Threshold)
+	if len(parsed) < threshold {
+		return nil, nil, errors.Wrapf(ErrInsufficientShares, "got %d shares, need %d", len(parsed), threshold)
+	}
+	parsed = parsed[:threshold]
+
+	data := make([]byte, len(parsed[0].Payload))
+	defer ZeroBytes(data)
+	for i := range data {
+		var value byte
+		for j, sj := range parsed {
+			// l_j(0) = Π x_m / (x_m - x_j)，GF(256) 中减法即异或
+			basis := byte(1)
+			for m, sm := range parsed {
+				if m != j {
+					basis = gf256Mul(basis, gf256Div(sm.X, sm.X^sj.X))
+				}
+			}
+			value ^= gf256Mul(sj.Payload[i], basis)
+		}
+		data[i] = value
+	}
+
+	secretLength := len(data) - shareChecksumLength
+	secret := append([]byte{}, data[:secretLength]...)
+	digest := sha256.Sum256(secret)
+	if !bytes.Equal(data[secretLength:], digest[:shareChecksumLength]) {
+		ZeroBytes(secret)
+		return nil, nil, errors.Wrap(ErrInvalidShare, "recovered secret checksum mismatch")
+	}
+	return parsed[0], secret, nil
+}
+
+func mnemonicLanguageIndex(lang MnemonicLanguage) byte {
+	for i, l := range mnemonicLanguages {
+		if l == lang {
+			return byte(i)
+		}
+	}
+	return 0
+}
+
+// GF(256) 运算，使用 AES 的既约多项式 x^8+x^4+x^3+x+1，生成元为3
+var gf256Exp, gf256Log = func() ([510]byte, [256]byte) {
+	var exp [510]byte
+	var log [256]byte
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		exp[i] = x
+		log[x] = byte(i)
+		// x *= 3
+		hi := x & 0x80
+		x2 := x << 1
+		if hi != 0 {
+			x2 ^= 0x1b
+		}
+		x ^= x2
+	}
+	for i := 255; i < len(exp); i++ {
+		exp[i] = exp[i-255]
+	}
+	return exp, log
+}()
+
+func gf256Mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gf256Exp[int(gf256Log[a])+int(gf256Log[b])]
+}
+
+// gf256Div 计算 a/b，b 不能为0
+func gf256Div(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gf256Exp[int(gf256Log[a])+255-int(gf256Log[b])]
+}
+
+// gf256EvalPolynomial 使用霍纳法则计算多项式在 x 处的值
+func gf256EvalPolynomial(coefficients []byte, x byte) byte {
+	var result byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result = gf256Mul(result, x) ^ coefficients[i]
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+package etherkit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGF256(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		for b := 1; b < 256; b++ {
+			if gf256Div(gf256Mul(byte(a), byte(b)), byte(b)) != byte(a) {
+				t.Fatalf("(%d*%d)/%d != %d", a, b, b, a)
+			}
+		}
+	}
+	// AES 标准中的例子：0x57 * 0x83 = 0xc1
+	if gf256Mul(0x57, 0x83) != 0xc1 {
+		t.Errorf("0x57*0x83 = %#x, expected 0xc1", gf256Mul(0x57, 0x83))
+	}
+}
+
+func TestSplitPrivateKey(t *testing.T) {
+	pk, err := BuildPrivateKeyFromHex(signerTestHexPk)
+	if err != nil {
+		t.Fatalf("BuildPrivateKeyFromHex() failed: %v", err)
+	}
+
+	shares, err := SplitPrivateKey(pk, 3, 5)
+	if err != nil {
+		t.Fatalf("SplitPrivateKey() failed: %v", err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("len(shares) = %d, expected 5", len(shares))
+	}
+	for _, share := range shares {
+		if err := ValidateShare(share); err != nil {
+			t.Errorf("ValidateShare() failed: %v", err)
+		}
+	}
+
+	// 任意3个分片都能恢复
+	for _, subset := range [][]string{
+		{shares[0], shares[1], shares[2]},
+		{shares[4], shares[2], shares[0]},
+		{shares[1], shares[3], shares[4], shares[0]},
+	} {
+		recovered, err := CombinePrivateKeyShares(subset)
+		if err != nil {
+			t.Fatalf("CombinePrivateKeyShares() failed: %v", err)
+		}
+		if GetHexPrivateKey(recovered) != signerTestHexPk {
+			t.Errorf("Recovered wrong private key")
+		}
+	}
+
+	signer, err := CombineSharesToSigner(shares[2:])
+	if err != nil {
+		t.Fatalf("CombineSharesToSigner() failed: %v", err)
+	}
+	if signer.GetAddress().Hex() != "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266" {
+		t.Errorf("Address = %s", signer.GetAddress().Hex())
+	}
+
+	if _, err := CombinePrivateKeyShares(shares[:2]); !errors.Is(err, ErrInsufficientShares) {
+		t.Errorf("Expected ErrInsufficientShares, got %v", err)
+	}
+	if _, err := CombinePrivateKeyShares([]string{shares[0], shares[0], shares[1]}); !errors.Is(err, ErrInsufficientShares) {
+		t.Errorf("Duplicate shares should not count, got %v", err)
+	}
+
+	// 不同拆分的分片不能混用
+	other, err := SplitPrivateKey(pk, 3, 5)
+	if err != nil {
+		t.Fatalf("SplitPrivateKey() failed: %v", err)
+	}
+	if _, err := CombinePrivateKeyShares([]string{shares[0], shares[1], other[2]}); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("Expected ErrInvalidShare, got %v", err)
+	}
+
+	for _, tt := range []struct{ threshold, shares int }{{1, 3}, {4, 3}, {2, 256}} {
+		if _, err := SplitPrivateKey(pk, tt.threshold, tt.shares); !errors.Is(err, ErrInvalidShare) {
+			t.Errorf("SplitPrivateKey(%d, %d) expected ErrInvalidShare, got %v", tt.threshold, tt.shares, err)
+		}
+	}
+}
+
+func TestSplitMnemonic(t *testing.T) {
+	mnemonic, err := GenerateMnemonicWithLanguage(Mnemonic24Words, MnemonicJapanese)
+	if err != nil {
+		t.Fatalf("GenerateMnemonicWithLanguage() failed: %v", err)
+	}
+
+	shares, err := SplitMnemonic(mnemonic, 2, 3)
+	if err != nil {
+		t.Fatalf("SplitMnemonic() failed: %v", err)
+	}
+	recovered, err := CombineMnemonicShares([]string{shares[2], shares[0]})
+	if err != nil {
+		t.Fatalf("CombineMnemonicShares() failed: %v", err)
+	}
+	if recovered != mnemonic {
+		t.Errorf("Recovered mnemonic = %s, expected %s", recovered, mnemonic)
+	}
+
+	if _, err := CombinePrivateKeyShares(shares); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("Expected ErrInvalidShare for mnemonic shares, got %v", err)
+	}
+
+	signer, err := CombineSharesToSigner(shares[1:])
+	if err != nil {
+		t.Fatalf("CombineSharesToSigner() failed: %v", err)
+	}
+	expected, err := NewSignerFromMnemonic(mnemonic)
+	if err != nil {
+		t.Fatalf("NewSignerFromMnemonic() failed: %v", err)
+	}
+	if signer.GetAddress() != expected.GetAddress() {
+		t.Errorf("Address = %s, expected %s", signer.GetAddress().Hex(), expected.GetAddress().Hex())
+	}
+
+	if _, err := SplitMnemonic("invalid mnemonic", 2, 3); !errors.Is(err, ErrInvalidMnemonic) {
+		t.Errorf("Expected ErrInvalidMnemonic, got %v", err)
+	}
+}
+
+func TestValidateShare(t *testing.T) {
+	shares, err := SplitMnemonic(hdTestMnemonic, 2, 2)
+	if err != nil {
+		t.Fatalf("SplitMnemonic() failed: %v", err)
+	}
+
+	// 修改一个字符
+	corrupted := []byte(shares[0])
+	if corrupted[20] == '0' {
+		corrupted[20] = '1'
+	} else {
+		corrupted[20] = '0'
+	}
+
+	for _, share := range []string{"", "zz", "01020304", string(corrupted)} {
+		if err := ValidateShare(share); !errors.Is(err, ErrInvalidShare) {
+			t.Errorf("ValidateShare(%q) expected ErrInvalidShare, got %v", share, err)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package etherkit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+//############ Signature Registry ############
+
+// identifierRegexp Solidity 标识符
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// seedFunctionSignatures 内置的常用方法签名：ERC20/721/1155、WETH、Multicall、Uniswap、Safe
+var seedFunctionSignatures = []string{
+	// ERC20 / EIP-2612
+	"name()", "symbol()", "decimals()", "totalSupply()", "balanceOf(address)", "allowance(address,address)",
+	"transfer(address,uint256)", "transferFrom(address,address,uint256)", "approve(address,uint256)",
+	"increaseAllowance(address,uint256)", "decreaseAllowance(address,uint256)",
+	"permit(address,address,uint256,uint256,uint8,bytes32,bytes32)", "nonces(address)", "DOMAIN_SEPARATOR()",
+	// ERC721
+	"ownerOf(uint256)", "getApproved(uint256)", "isApprovedForAll(address,address)", "setApprovalForAll(address,bool)",
+	"safeTransferFrom(address,address,uint256)", "safeTransferFrom(address,address,uint256,bytes)",
+	"tokenURI(uint256)", "supportsInterface(bytes4)",
+	// ERC1155
+	"balanceOf(address,uint256)", "balanceOfBatch(address[],uint256[])", "uri(uint256)",
+	"safeTransferFrom(address,address,uint256,uint256,bytes)", "safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)",
+	// WETH
+	"deposit()", "withdraw(uint256)",
+	// Multicall / Multicall3
+	"aggregate((address,bytes)[])", "tryAggregate(bool,(address,bytes)[])", "blockAndAggregate((address,bytes)[])",
+	"tryBlockAndAggregate(bool,(address,bytes)[])", "aggregate3((address,bool,bytes)[])",
+	"aggregate3Value((address,bool,uint256,bytes)[])", "multicall(bytes[])", "multicall(uint256,bytes[])",
+	"multicall(bytes32,bytes[])",
+	// Uniswap V2
+	"getReserves()", "getAmountsOut(uint256,address[])", "getAmountsIn(uint256,address[])",
+	"swapExactTokensForTokens(uint256,uint256,address[],address,uint256)",
+	"swapTokensForExactTokens(uint256,uint256,address[],address,uint256)",
+	"swapExactETHForTokens(uint256,address[],address,uint256)",
+	"swapTokensForExactETH(uint256,uint256,address[],address,uint256)",
+	"swapExactTokensForETH(uint256,uint256,address[],address,uint256)",
+	"swapETHForExactTokens(uint256,address[],address,uint256)",
+	"addLiquidity(address,address,uint256,uint256,uint256,uint256,address,uint256)",
+	"addLiquidityETH(address,uint256,uint256,uint256,address,uint256)",
+	"removeLiquidity(address,address,uint256,uint256,uint256,address,uint256)",
+	"removeLiquidityETH(address,uint256,uint256,uint256,address,uint256)",
+	// Uniswap V3 / Universal Router
+	"exactInputSingle((address,address,uint24,address,uint256,uint256,uint256,uint160))",
+	"exactInput((bytes,address,uint256,uint256,uint256))",
+	"exactOutputSingle((address,address,uint24,address,uint256,uint256,uint256,uint160))",
+	"exactOutput((bytes,address,uint256,uint256,uint256))",
+	"execute(bytes,bytes[])", "execute(bytes,bytes[],uint256)",
+	// Safe
+	"execTransaction(address,uint256,bytes,uint8,uint256,uint256,uint256,address,address,bytes)",
+	"multiSend(bytes)", "approveHash(bytes32)", "addOwnerWithThreshold(address,uint256)",
+	"removeOwner(address,address,uint256)", "swapOwner(address,address,address)", "changeThreshold(uint256)",
+	"enableModule(address)", "disableModule(address,address)",
+	"setup(address[],uint256,address,bytes,address,address,uint256,address)",
+	// 其他常见方法
+	"owner()", "transferOwnership(address)", "renounceOwnership()",
+	"grantRole(bytes32,address)", "revokeRole(bytes32,address)", "hasRole(bytes32,address)",
+	"pause()", "unpause()", "paused()", "mint(address,uint256)", "burn(uint256)", "burnFrom(address,uint256)",
+	"upgradeTo(address)", "upgradeToAndCall(address,bytes)", "isValidSignature(bytes32,bytes)",
+}
+
+// seedEventSignatures 内置的常用事件签名
+var seedEventSignatures = []string{
+	// ERC20 / ERC721
+	"Transfer(address,address,uint256)", "Approval(address,address,uint256)", "ApprovalForAll(address,address,bool)",
+	// ERC1155
+	"TransferSingle(address,address,address,uint256,uint256)", "TransferBatch(address,address,address,uint256[],uint256[])",
+	"URI(string,uint256)",
+	// WETH
+	"Deposit(address,uint256)", "Withdrawal(address,uint256)",
+	// Uniswap V2 / V3
+	"Swap(address,uint256,uint256,uint256,uint256,address)", "Sync(uint112,uint112)",
+	"Mint(address,uint256,uint256)", "Burn(address,uint256,uint256,address)",
+	"PairCreated(address,address,address,uint256)",
+	"Swap(address,address,int256,int256,uint160,uint128,int24)", "PoolCreated(address,address,uint24,int24,address)",
+	// Safe
+	"ExecutionSuccess(bytes32,uint256)", "ExecutionFailure(bytes32,uint256)",
+	"SafeSetup(address,address[],uint256,address,address)", "SafeReceived(address,uint256)",
+	"AddedOwner(address)", "RemovedOwner(address)", "ChangedThreshold(uint256)",
+	"EnabledModule(address)", "DisabledModule(address)",
+	// OpenZeppelin
+	"OwnershipTransferred(address,address)", "Paused(address)", "Unpaused(address)",
+	"RoleGranted(bytes32,address,address)", "RoleRevoked(bytes32,address,address)",
+	"Upgraded(address)", "AdminChanged(address,address)", "Initialized(uint8)", "Initialized(uint64)",
+}
+
+// SignatureRegistry 选择器/事件 topic 到文本签名的反查表。
+// 同一选择器可能对应多个签名（碰撞），查询时按添加顺序返回全部候选。并发安全
+type SignatureRegistry struct {
+	mu        sync.RWMutex
+	functions map[[4]byte][]string
+	events    map[common.Hash][]string
+}
+
+// NewSignatureRegistry 创建空的签名库
+func NewSignatureRegistry() *SignatureRegistry {
+	return &SignatureRegistry{
+		functions: make(map[[4]byte][]string),
+		events:    make(map[common.Hash][]string),
+	}
+}
+
+// NewDefaultSignatureRegistry 创建包含内置常用签名的签名库
+func NewDefaultSignatureRegistry() *SignatureRegistry {
+	r := NewSignatureRegistry()
+	for _, sig := range seedFunctionSignatures {
+		if err := r.AddFunction(sig); err != nil {
+			panic(err)
+		}
+	}
+	for _, sig := range seedEventSignatures {
+		if err := r.AddEvent(sig); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// AddFunction 添加方法签名，如 transfer(address,uint256)
+func (r *SignatureRegistry) AddFunction(signature string) error {
+	sig, err := canonicalSignature(signature, methodSignature)
+	if err != nil {
+		return err
+	}
+	var selector [4]byte
+	copy(selector[:], crypto.Keccak256([]byte(sig))[:4])
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.functions[selector] = appendUnique(r.functions[selector], sig)
+	return nil
+}
+
+// AddEvent 添加事件签名，如 Transfer(address,address,uint256)，方法和错误签名返回 ErrInvalidTextSignature
+func (r *SignatureRegistry) AddEvent(signature string) error {
+	sig, err := canonicalSignature(signature, eventSignature)
+	if err != nil {
+		return err
+	}
+	topic := crypto.Keccak256Hash([]byte(sig))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events[topic] = appendUnique(r.events[topic], sig)
+	return nil
+}
+
+// AddABI 添加 ABI 中的全部方法和事件
+func (r *SignatureRegistry) AddABI(contractAbi abi.ABI) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, method := range contractAbi.Methods {
+		var selector [4]byte
+		copy(selector[:], method.ID)
+		r.functions[selector] = appendUnique(r.functions[selector], method.Sig)
+	}
+	for _, event := range contractAbi.Events {
+		r.events[event.ID] = appendUnique(r.events[event.ID], event.Sig)
+	}
+}
+
+// LookupFunction 查询选择器对应的候选方法签名，selector 可以是 4 字节或更长的 calldata
+func (r *SignatureRegistry) LookupFunction(selector []byte) []string {
+	if len(selector) < 4 {
+		return nil
+	}
+	var key [4]byte
+	copy(key[:], selector[:4])
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.functions[key]...)
+}
+
+// LookupEvent 查询 topic0 对应的候选事件签名
+func (r *SignatureRegistry) LookupEvent(topic common.Hash) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.events[topic]...)
+}
+
+// Len 方法签名和事件签名的数量
+func (r *SignatureRegistry) Len() (functions, events int) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, sigs := range r.functions {
+		functions += len(sigs)
+	}
+	for _, sigs := range r.events {
+		events += len(sigs)
+	}
+	return functions, events
+}
+
+// DecodeCalldata 用候选签名解码 calldata，只保留能够完整解码且重新编码结果是原数据前缀的候选，
+// 以排除选择器碰撞产生的错误结果。编码之后附加的字节（如追踪后缀）会被忽略，
+// 完全一致的候选排在前面。没有候选时返回 ErrMethodNotFound
+func (r *SignatureRegistry) DecodeCalldata(data []byte) ([]*DecodedCall, error) {
+	if len(data) < 4 {
+		return nil, errors.Wrapf(ErrInvalidCalldata, "calldata is %d bytes", len(data))
+	}
+	var calls, prefixCalls []*DecodedCall
+	for _, sig := range r.LookupFunction(data) {
+		name, args, err := parseTextSignature(sig)
+		if err != nil {
+			continue
+		}
+		values, err := args.Unpack(data[4:])
+		if err != nil {
+			continue
+		}
+		packed, err := args.Pack(values...)
+		if err != nil || !bytes.HasPrefix(data[4:], packed) {
+			continue
+		}
+		call := &DecodedCall{Method: name, Signature: sig, Selector: hexutil.Encode(data[:4]), Args: make([]DecodedArg, len(values))}
+		for i, arg := range args {
+			call.Args[i] = DecodedArg{Type: arg.Type.String(), Value: values[i]}
+		}
+		if len(packed) == len(data)-4 {
+			calls = append(calls, call)
+		} else {
+			prefixCalls = append(prefixCalls, call)
+		}
+	}
+	calls = append(calls, prefixCalls...)
+	if len(calls) == 0 {
+		return nil, errors.Wrapf(ErrMethodNotFound, "selector %s", hexutil.Encode(data[:4]))
+	}
+	return calls, nil
+}
+
+//############ Signature Import ############
+
+// fourByteEntry 4byte.directory API 的返回条目
+type fourByteEntry struct {
+	HexSignature  string `json:"hex_signature"`
+	TextSignature string `json:"text_signature"`
+}
+
+// ImportFunctions 导入方法签名，返回新增的数量。支持以下格式：
+//   - 4byte.directory API 的 JSON（{"results": [...]} 或条目数组）
+//   - 每行一个签名，可带选择器前缀，如 "0xa9059cbb transfer(address,uint256)" 或 "0xa9059cbb,transfer(...)"
+//
+// 选择器与签名不一致或签名无效的条目会被跳过
+func (r *SignatureRegistry) ImportFunctions(reader io.Reader) (int, error) {
+	return r.importSignatures(reader, 4, r.AddFunction, r.LookupFunction)
+}
+
+// ImportEvents 导入事件签名，格式同 ImportFunctions，前缀为 32 字节的 topic
+func (r *SignatureRegistry) ImportEvents(reader io.Reader) (int, error) {
+	return r.importSignatures(reader, 32, r.AddEvent, func(hash []byte) []string {
+		return r.LookupEvent(common.BytesToHash(hash))
+	})
+}
+
+func (r *SignatureRegistry) importSignatures(reader io.Reader, hashLength int, add func(string) error, lookup func([]byte) []string) (int, error) {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return 0, err
+	}
+
+	var entries []fourByteEntry
+	trimmed := bytes.TrimSpace(content)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var page struct {
+			Results []fourByteEntry `json:"results"`
+		}
+		if err := json.Unmarshal(trimmed, &page); err != nil {
+			return 0, errors.Wrap(err, "failed to decode signature JSON")
+		}
+		entries = page.Results
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return 0, errors.Wrap(err, "failed to decode signature JSON")
+		}
+	default:
+		scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			entry := fourByteEntry{TextSignature: line}
+			if strings.HasPrefix(line, "0x") {
+				if i := strings.IndexAny(line, " \t,:="); i > 0 {
+					entry.HexSignature, entry.TextSignature = line[:i], strings.TrimLeft(line[i:], " \t,:=")
+				}
+			}
+			entries = append(entries, entry)
+		}
+		if err := scanner.Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	added := 0
+	for _, entry := range entries {
+		sig, err := CanonicalSignature(entry.TextSignature)
+		if err != nil {
+			continue
+		}
+		hash := crypto.Keccak256([]byte(sig))[:hashLength]
+		if entry.HexSignature != "" {
+			expected, err := hexutil.Decode(entry.HexSignature)
+			if err != nil || !bytes.Equal(expected, hash) {
+				continue
+			}
+		}
+		if containsString(lookup(hash), sig) {
+			continue
+		}
+		if err := add(sig); err == nil {
+			added++
+		}
+	}
+	return added, nil
+}
+
+// parseTextSignature 解析签名并转换为 abi.Arguments，用于按候选签名解码数据
+func parseTextSignature(signature string) (string, abi.Arguments, error) {
+	name, inputs, err := parseSignatureFragment(signature, methodSignature)
+	if err != nil {
+		return "", nil, err
+	}
+	args := make(abi.Arguments, len(inputs))
+	for i, input := range inputs {
+		typ, err := abi.NewType(input.Type, "", input.Components)
+		if err != nil {
+			return "", nil, errors.Wrapf(ErrInvalidTextSignature, "%s: %v", signature, err)
+		}
+		args[i] = abi.Argument{Type: typ}
+	}
+	return name, args, nil
+}
+
+func appendUnique(values []string, value string) []string {
+	if containsString(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
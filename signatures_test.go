@@ -0,0 +1,115 @@
+package etherkit
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/guanzhenxing/go-evm-kit/contracts/erc20"
+)
+
+const signaturesTestABI = `[
+	{"name":"totalSupply","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"getPool","type":"function","stateMutability":"view","inputs":[{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+	{"name":"setFee","type":"function","stateMutability":"nonpayable","inputs":[{"name":"fee","type":"uint16"}],"outputs":[]}
+]`
+
+var signaturesTestRecipient = common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+func TestSignatureRegistry(t *testing.T) {
+	registry := NewDefaultSignatureRegistry()
+
+	if sigs := registry.LookupFunction(hexutil.MustDecode(ERC20TransferMethodID)); len(sigs) != 1 || sigs[0] != "transfer(address,uint256)" {
+		t.Errorf("LookupFunction(transfer) = %v", sigs)
+	}
+	if sigs := registry.LookupEvent(common.HexToHash(ERC20TransferEventTopic)); len(sigs) != 1 || sigs[0] != "Transfer(address,address,uint256)" {
+		t.Errorf("LookupEvent(Transfer) = %v", sigs)
+	}
+	if sigs := registry.LookupFunction(hexutil.MustDecode("0x6a761202")); len(sigs) != 1 || !strings.HasPrefix(sigs[0], "execTransaction(") {
+		t.Errorf("LookupFunction(execTransaction) = %v", sigs)
+	}
+
+	// many_msg_babbage(bytes1) 与 transfer(address,uint256) 的选择器相同
+	if err := registry.AddFunction("many_msg_babbage(bytes1)"); err != nil {
+		t.Fatalf("AddFunction() failed: %v", err)
+	}
+	if sigs := registry.LookupFunction(hexutil.MustDecode(ERC20TransferMethodID)); len(sigs) != 2 {
+		t.Fatalf("Expected collision candidates, got %v", sigs)
+	}
+	erc20Abi, _ := erc20.IERC20MetaData.GetAbi()
+	transfer, _ := erc20Abi.Pack("transfer", signaturesTestRecipient, big.NewInt(1000))
+	calls, err := registry.DecodeCalldata(transfer)
+	if err != nil || len(calls) != 1 || calls[0].Method != "transfer" || calls[0].Args[0].Value != signaturesTestRecipient {
+		t.Errorf("DecodeCalldata() = %v, %v", calls, err)
+	}
+
+	// 编码之后附加的追踪后缀会被忽略
+	tagged := append(append([]byte(nil), transfer...), hexutil.MustDecode("0xdeadbeefcafe")...)
+	calls, err = registry.DecodeCalldata(tagged)
+	if err != nil || len(calls) != 1 || calls[0].Method != "transfer" || calls[0].Args[1].Value.(*big.Int).Int64() != 1000 {
+		t.Errorf("DecodeCalldata() with suffix = %v, %v", calls, err)
+	}
+
+	if err := registry.AddFunction("transfer(address,uint256"); !errors.Is(err, ErrInvalidTextSignature) {
+		t.Errorf("Expected ErrInvalidTextSignature, got %v", err)
+	}
+	if err := registry.AddEvent("Swap(address, (uint256,bytes)[] )"); err != nil {
+		t.Errorf("AddEvent() with tuple failed: %v", err)
+	}
+	if err := registry.AddEvent("function transfer(address,uint256)"); !errors.Is(err, ErrInvalidTextSignature) {
+		t.Errorf("AddEvent() with function signature expected ErrInvalidTextSignature, got %v", err)
+	}
+	if _, err := registry.DecodeCalldata([]byte{0xde, 0xad, 0xbe, 0xef}); !errors.Is(err, ErrMethodNotFound) {
+		t.Errorf("Expected ErrMethodNotFound, got %v", err)
+	}
+}
+
+func TestSignatureRegistryImport(t *testing.T) {
+	registry := NewSignatureRegistry()
+
+	fourByte := `{"count": 3, "results": [
+		{"hex_signature": "0xa9059cbb", "text_signature": "transfer(address,uint256)"},
+		{"hex_signature": "0xa9059cbb", "text_signature": "many_msg_babbage(bytes1)"},
+		{"hex_signature": "0x12345678", "text_signature": "approve(address,uint256)"}
+	]}`
+	n, err := registry.ImportFunctions(strings.NewReader(fourByte))
+	if err != nil || n != 2 {
+		t.Errorf("ImportFunctions(json) = %d, %v", n, err)
+	}
+
+	lines := "# selectors\n0x095ea7b3 approve(address,uint256)\n0x70a08231,balanceOf(address)\ntotalSupply()\ntransfer(address,uint256)\nnot a signature\n"
+	n, err = registry.ImportFunctions(strings.NewReader(lines))
+	if err != nil || n != 3 {
+		t.Errorf("ImportFunctions(lines) = %d, %v", n, err)
+	}
+
+	n, err = registry.ImportEvents(strings.NewReader(ERC20TransferEventTopic + " Transfer(address,address,uint256)\n"))
+	if err != nil || n != 1 {
+		t.Errorf("ImportEvents() = %d, %v", n, err)
+	}
+
+	contractAbi, err := GetABI(signaturesTestABI)
+	if err != nil {
+		t.Fatalf("GetABI() failed: %v", err)
+	}
+	registry.AddABI(contractAbi)
+	if sigs := registry.LookupFunction(contractAbi.Methods["getPool"].ID); len(sigs) != 1 || sigs[0] != "getPool(uint256)" {
+		t.Errorf("LookupFunction(getPool) = %v", sigs)
+	}
+	if functions, events := registry.Len(); functions != 7 || events != 1 {
+		t.Errorf("Len() = %d, %d", functions, events)
+	}
+
+	// 解码器在 ABI 未知时回退到签名库
+	decoder := NewCalldataDecoder()
+	decoder.SetSignatureRegistry(registry)
+	approve := append(hexutil.MustDecode(ERC20ApproveMethodID), common.LeftPadBytes(signaturesTestRecipient.Bytes(), 32)...)
+	approve = append(approve, common.LeftPadBytes([]byte{1}, 32)...)
+	call, err := decoder.Decode(approve)
+	if err != nil || call.Signature != "approve(address,uint256)" {
+		t.Errorf("Decode() = %+v, %v", call, err)
+	}
+}
@@ -1,7 +1,13 @@
 package etherkit
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
 	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -14,7 +20,9 @@ type EtherSigner interface {
 	GetPrivateKey() *ecdsa.PrivateKey
 }
 
+// Signer 持有明文私钥的账户。String/Format/MarshalJSON 只输出地址，不会输出私钥
 type Signer struct {
+	mu      sync.RWMutex
 	pk      *ecdsa.PrivateKey
 	address common.Address
 }
@@ -50,6 +58,24 @@ func NewSignerFromMnemonicAndAccountId(mnemonic string, accountId uint32) (*Sign
 	return NewSignerFromPrivateKey(pk)
 }
 
+// NewSignerFromMnemonicWithPassphrase 使用助记词和 BIP39 passphrase 创建一个账号信息
+func NewSignerFromMnemonicWithPassphrase(mnemonic, passphrase string, accountId uint32) (*Signer, error) {
+	pk, err := BuildPrivateKeyFromMnemonicWithPassphrase(mnemonic, passphrase, accountId)
+	if err != nil {
+		return nil, err
+	}
+	return NewSignerFromPrivateKey(pk)
+}
+
+// NewSignerFromMnemonicAndPath 使用助记词、BIP39 passphrase 和任意派生路径创建一个账号信息
+func NewSignerFromMnemonicAndPath(mnemonic, passphrase, path string) (*Signer, error) {
+	pk, err := BuildPrivateKeyFromMnemonicAndPath(mnemonic, passphrase, path)
+	if err != nil {
+		return nil, err
+	}
+	return NewSignerFromPrivateKey(pk)
+}
+
 // NewSignerFromRawPrivateKey 使用私钥创建一个账号信息
 func NewSignerFromRawPrivateKey(rawPk []byte) (*Signer, error) {
 	pk, err := crypto.ToECDSA(rawPk)
@@ -73,7 +99,291 @@ func (s *Signer) GetAddress() common.Address {
 	return s.address
 }
 
-// GetPrivateKey 获得私钥
+// GetPrivateKey 获得私钥，Signer 销毁后返回 nil
 func (s *Signer) GetPrivateKey() *ecdsa.PrivateKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.pk
 }
+
+// WithPrivateKey 在持有私钥期间执行 fn，fn 不应保留私钥的引用
+func (s *Signer) WithPrivateKey(fn func(pk *ecdsa.PrivateKey) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.pk == nil {
+		return ErrSignerDestroyed
+	}
+	return fn(s.pk)
+}
+
+// SignTypedData 对EIP-712结构化数据签名
+func (s *Signer) SignTypedData(typedData *TypedData) (signature []byte, err error) {
+	err = s.WithPrivateKey(func(pk *ecdsa.PrivateKey) error {
+		signature, err = SignTypedData(pk, typedData)
+		return err
+	})
+	return signature, err
+}
+
+// SignPersonalMessage 对 EIP-191 personal_sign 消息签名
+func (s *Signer) SignPersonalMessage(message []byte) (signature []byte, err error) {
+	err = s.WithPrivateKey(func(pk *ecdsa.PrivateKey) error {
+		signature, err = SignPersonalMessage(pk, message)
+		return err
+	})
+	return signature, err
+}
+
+// Destroy 清零并丢弃私钥，之后所有签名操作都会返回 ErrSignerDestroyed。
+// 注意通过 GetPrivateKey 取得的私钥与 Signer 共享内存，也会一并被清零
+func (s *Signer) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ZeroPrivateKey(s.pk)
+	s.pk = nil
+}
+
+// IsDestroyed Signer 是否已经被销毁
+func (s *Signer) IsDestroyed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.pk == nil
+}
+
+// Encrypt 将私钥加密后转换为 EncryptedSigner，并销毁当前 Signer
+func (s *Signer) Encrypt() (*EncryptedSigner, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pk == nil {
+		return nil, ErrSignerDestroyed
+	}
+	es, err := newEncryptedSigner(s.pk)
+	if err != nil {
+		return nil, err
+	}
+	ZeroPrivateKey(s.pk)
+	s.pk = nil
+	return es, nil
+}
+
+// String 只输出地址
+func (s *Signer) String() string {
+	return redactedSignerString("Signer", s.address)
+}
+
+// Format 实现 fmt.Formatter，保证 %v/%+v/%#v/%s 等任何格式都不会输出私钥
+func (s *Signer) Format(f fmt.State, verb rune) {
+	_, _ = fmt.Fprint(f, s.String())
+}
+
+// MarshalJSON 只序列化地址
+func (s *Signer) MarshalJSON() ([]byte, error) {
+	return marshalRedactedSigner(s.address)
+}
+
+//############ Encrypted Signer ############
+
+// EncryptedSigner 在内存中以 AES-256-GCM 加密保存私钥的账户，适用于长期运行的进程。
+// 私钥只在签名时临时解密，使用后立即清零，避免明文私钥长期驻留在堆上或随结构体被意外输出。
+// 加密密钥在进程内首次使用时随机生成，由所有 EncryptedSigner 共享且不保存在结构体中；
+// 能读取整个进程内存的攻击者仍然可以还原私钥
+type EncryptedSigner struct {
+	mu         sync.RWMutex
+	nonce      []byte
+	ciphertext []byte
+	address    common.Address
+}
+
+var (
+	signerAEADOnce sync.Once
+	signerAEAD     cipher.AEAD
+	signerAEADErr  error
+)
+
+// encryptedSignerAEAD 获得进程内共享的 AES-256-GCM 实例，密钥在首次调用时随机生成
+func encryptedSignerAEAD() (cipher.AEAD, error) {
+	signerAEADOnce.Do(func() {
+		key := make([]byte, 32)
+		defer ZeroBytes(key)
+		if _, err := rand.Read(key); err != nil {
+			signerAEADErr = errors.Wrap(err, "failed to generate encryption key")
+			return
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			signerAEADErr = errors.Wrap(err, "failed to create cipher")
+			return
+		}
+		signerAEAD, err = cipher.NewGCM(block)
+		if err != nil {
+			signerAEADErr = errors.Wrap(err, "failed to create cipher")
+		}
+	})
+	return signerAEAD, signerAEADErr
+}
+
+// NewEncryptedSigner 使用私钥创建 EncryptedSigner，传入的私钥会被清零
+func NewEncryptedSigner(pk *ecdsa.PrivateKey) (*EncryptedSigner, error) {
+	if pk == nil || pk.D == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	es, err := newEncryptedSigner(pk)
+	if err != nil {
+		return nil, err
+	}
+	ZeroPrivateKey(pk)
+	return es, nil
+}
+
+// NewEncryptedSignerFromHexPrivateKey 使用十六进制私钥创建 EncryptedSigner
+func NewEncryptedSignerFromHexPrivateKey(hexPk string) (*EncryptedSigner, error) {
+	pk, err := BuildPrivateKeyFromHex(hexPk)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid hex private key")
+	}
+	return NewEncryptedSigner(pk)
+}
+
+func newEncryptedSigner(pk *ecdsa.PrivateKey) (*EncryptedSigner, error) {
+	aead, err := encryptedSignerAEAD()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+
+	address := PrivateKeyToAddress(pk)
+	plaintext := GetPrivateKeyBytes(pk)
+	defer ZeroBytes(plaintext)
+
+	return &EncryptedSigner{
+		nonce:      nonce,
+		ciphertext: aead.Seal(nil, nonce, plaintext, address.Bytes()),
+		address:    address,
+	}, nil
+}
+
+// GetAddress 获得地址
+func (s *EncryptedSigner) GetAddress() common.Address {
+	return s.address
+}
+
+// GetPrivateKey 解密出一份私钥的拷贝，调用方使用完后应调用 ZeroPrivateKey 清零，
+// 优先使用 WithPrivateKey。Signer 销毁或解密失败时返回 nil
+func (s *EncryptedSigner) GetPrivateKey() *ecdsa.PrivateKey {
+	pk, err := s.decrypt()
+	if err != nil {
+		return nil
+	}
+	return pk
+}
+
+// WithPrivateKey 解密私钥并执行 fn，fn 返回后私钥立即被清零
+func (s *EncryptedSigner) WithPrivateKey(fn func(pk *ecdsa.PrivateKey) error) error {
+	pk, err := s.decrypt()
+	if err != nil {
+		return err
+	}
+	defer ZeroPrivateKey(pk)
+	return fn(pk)
+}
+
+func (s *EncryptedSigner) decrypt() (*ecdsa.PrivateKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.ciphertext == nil {
+		return nil, ErrSignerDestroyed
+	}
+	aead, err := encryptedSignerAEAD()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, s.nonce, s.ciphertext, s.address.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidPrivateKey, "failed to decrypt private key")
+	}
+	defer ZeroBytes(plaintext)
+	pk, err := crypto.ToECDSA(plaintext)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidPrivateKey, err.Error())
+	}
+	return pk, nil
+}
+
+// SignTypedData 对EIP-712结构化数据签名
+func (s *EncryptedSigner) SignTypedData(typedData *TypedData) (signature []byte, err error) {
+	err = s.WithPrivateKey(func(pk *ecdsa.PrivateKey) error {
+		signature, err = SignTypedData(pk, typedData)
+		return err
+	})
+	return signature, err
+}
+
+// SignPersonalMessage 对 EIP-191 personal_sign 消息签名
+func (s *EncryptedSigner) SignPersonalMessage(message []byte) (signature []byte, err error) {
+	err = s.WithPrivateKey(func(pk *ecdsa.PrivateKey) error {
+		signature, err = SignPersonalMessage(pk, message)
+		return err
+	})
+	return signature, err
+}
+
+// Destroy 清零密文，之后所有签名操作都会返回 ErrSignerDestroyed
+func (s *EncryptedSigner) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ZeroBytes(s.ciphertext)
+	ZeroBytes(s.nonce)
+	s.ciphertext, s.nonce = nil, nil
+}
+
+// IsDestroyed Signer 是否已经被销毁
+func (s *EncryptedSigner) IsDestroyed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ciphertext == nil
+}
+
+// String 只输出地址
+func (s *EncryptedSigner) String() string {
+	return redactedSignerString("EncryptedSigner", s.address)
+}
+
+// Format 实现 fmt.Formatter，保证任何格式都不会输出密钥或密文
+func (s *EncryptedSigner) Format(f fmt.State, verb rune) {
+	_, _ = fmt.Fprint(f, s.String())
+}
+
+// MarshalJSON 只序列化地址
+func (s *EncryptedSigner) MarshalJSON() ([]byte, error) {
+	return marshalRedactedSigner(s.address)
+}
+
+// privateKeyHolder 支持在回调中临时使用私钥的签名者
+type privateKeyHolder interface {
+	WithPrivateKey(fn func(pk *ecdsa.PrivateKey) error) error
+}
+
+// withSignerPrivateKey 使用签名者的私钥执行 fn，优先使用 WithPrivateKey 以便及时清零
+func withSignerPrivateKey(es EtherSigner, fn func(pk *ecdsa.PrivateKey) error) error {
+	if holder, ok := es.(privateKeyHolder); ok {
+		return holder.WithPrivateKey(fn)
+	}
+	pk := es.GetPrivateKey()
+	if pk == nil {
+		return ErrSignerDestroyed
+	}
+	return fn(pk)
+}
+
+func redactedSignerString(name string, address common.Address) string {
+	return fmt.Sprintf("%s(%s)", name, address.Hex())
+}
+
+func marshalRedactedSigner(address common.Address) ([]byte, error) {
+	return json.Marshal(struct {
+		Address string `json:"address"`
+	}{address.Hex()})
+}
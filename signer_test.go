@@ -0,0 +1,139 @@
+package etherkit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const signerTestHexPk = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+func TestSignerRedaction(t *testing.T) {
+	signer, err := NewSignerFromHexPrivateKey(signerTestHexPk)
+	if err != nil {
+		t.Fatalf("NewSignerFromHexPrivateKey() failed: %v", err)
+	}
+	encrypted, err := NewEncryptedSignerFromHexPrivateKey(signerTestHexPk)
+	if err != nil {
+		t.Fatalf("NewEncryptedSignerFromHexPrivateKey() failed: %v", err)
+	}
+	wallet, err := NewWalletWithComponents(signer, nil)
+	if err != nil {
+		t.Fatalf("NewWalletWithComponents() failed: %v", err)
+	}
+
+	for _, v := range []interface{}{signer, encrypted, wallet} {
+		outputs := []string{
+			fmt.Sprint(v),
+			fmt.Sprintf("%v", v),
+			fmt.Sprintf("%+v", v),
+			fmt.Sprintf("%#v", v),
+			fmt.Sprintf("%s", v),
+			fmt.Sprintf("%x", v),
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("json.Marshal() failed: %v", err)
+		}
+		outputs = append(outputs, string(data))
+
+		for _, output := range outputs {
+			if strings.Contains(strings.ToLower(output), signerTestHexPk) {
+				t.Errorf("Output leaks private key: %s", output)
+			}
+			if !strings.Contains(output, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266") {
+				t.Errorf("Output should contain address: %s", output)
+			}
+		}
+	}
+}
+
+func TestSignerDestroy(t *testing.T) {
+	signer, err := NewSignerFromHexPrivateKey(signerTestHexPk)
+	if err != nil {
+		t.Fatalf("NewSignerFromHexPrivateKey() failed: %v", err)
+	}
+	pk := signer.GetPrivateKey()
+
+	signer.Destroy()
+	if !signer.IsDestroyed() || signer.GetPrivateKey() != nil {
+		t.Error("Signer should be destroyed")
+	}
+	if pk.D.Sign() != 0 {
+		t.Error("Private key should be zeroed")
+	}
+	if _, err := signer.SignPersonalMessage([]byte("hello")); !errors.Is(err, ErrSignerDestroyed) {
+		t.Errorf("Expected ErrSignerDestroyed, got %v", err)
+	}
+
+	wallet, err := NewWalletWithComponents(signer, nil)
+	if err != nil {
+		t.Fatalf("NewWalletWithComponents() failed: %v", err)
+	}
+	if _, err := wallet.Signature([]byte("hello")); !errors.Is(err, ErrSignerDestroyed) {
+		t.Errorf("Expected ErrSignerDestroyed, got %v", err)
+	}
+}
+
+func TestEncryptedSigner(t *testing.T) {
+	signer, err := NewSignerFromHexPrivateKey(signerTestHexPk)
+	if err != nil {
+		t.Fatalf("NewSignerFromHexPrivateKey() failed: %v", err)
+	}
+	expected, err := signer.SignPersonalMessage([]byte("hello"))
+	if err != nil {
+		t.Fatalf("SignPersonalMessage() failed: %v", err)
+	}
+
+	encrypted, err := signer.Encrypt()
+	if err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+	if !signer.IsDestroyed() {
+		t.Error("Encrypt() should destroy the plaintext signer")
+	}
+	if encrypted.GetAddress() != signer.GetAddress() {
+		t.Errorf("Address = %s, expected %s", encrypted.GetAddress().Hex(), signer.GetAddress().Hex())
+	}
+
+	signature, err := encrypted.SignPersonalMessage([]byte("hello"))
+	if err != nil {
+		t.Fatalf("SignPersonalMessage() failed: %v", err)
+	}
+	if string(signature) != string(expected) {
+		t.Error("EncryptedSigner signature mismatch")
+	}
+
+	// 通过钱包签名
+	wallet, err := NewWalletWithComponents(encrypted, nil)
+	if err != nil {
+		t.Fatalf("NewWalletWithComponents() failed: %v", err)
+	}
+	signature, err = wallet.SignPersonalMessage([]byte("hello"))
+	if err != nil {
+		t.Fatalf("Wallet.SignPersonalMessage() failed: %v", err)
+	}
+	if string(signature) != string(expected) {
+		t.Error("Wallet signature mismatch")
+	}
+
+	// GetPrivateKey 每次返回新的拷贝
+	pk := encrypted.GetPrivateKey()
+	if GetHexPrivateKey(pk) != signerTestHexPk {
+		t.Error("GetPrivateKey() returned wrong key")
+	}
+	ZeroPrivateKey(pk)
+	if _, err := encrypted.SignPersonalMessage([]byte("hello")); err != nil {
+		t.Errorf("Zeroing a copy should not affect the signer: %v", err)
+	}
+
+	encrypted.Destroy()
+	if !encrypted.IsDestroyed() || encrypted.GetPrivateKey() != nil {
+		t.Error("EncryptedSigner should be destroyed")
+	}
+	if _, err := encrypted.SignPersonalMessage([]byte("hello")); !errors.Is(err, ErrSignerDestroyed) {
+		t.Errorf("Expected ErrSignerDestroyed, got %v", err)
+	}
+}
@@ -0,0 +1,388 @@
+package etherkit
+
+import (
+	"crypto/rand"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+//############ Sign-In with Ethereum (EIP-4361) ############
+
+const (
+	siweHeaderSuffix   = " wants you to sign in with your Ethereum account:"
+	siweVersion        = "1"
+	siweNonceAlphabet  = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	siweNonceMinLength = 8
+	siweNonceLength    = 17
+)
+
+const (
+	siweURITag            = "URI: "
+	siweVersionTag        = "Version: "
+	siweChainIDTag        = "Chain ID: "
+	siweNonceTag          = "Nonce: "
+	siweIssuedAtTag       = "Issued At: "
+	siweExpirationTimeTag = "Expiration Time: "
+	siweNotBeforeTag      = "Not Before: "
+	siweRequestIDTag      = "Request ID: "
+	siweResourcesTag      = "Resources:"
+	siweResourcePrefix    = "- "
+)
+
+// SiweMessage Sign-In with Ethereum 消息
+type SiweMessage struct {
+	Scheme         string // 可选，如 https
+	Domain         string
+	Address        common.Address
+	Statement      string // 可选
+	URI            string
+	Version        string
+	ChainID        int64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time // 可选
+	NotBefore      *time.Time // 可选
+	RequestID      string     // 可选
+	Resources      []string   // 可选
+
+	// 解析时保留的原始时间文本（如 2021-09-30T16:25:24.000Z），
+	// 时间未被修改时 String() 原样输出，保证与被签名的文本一致
+	issuedAtText       siweTimeText
+	expirationTimeText siweTimeText
+	notBeforeText      siweTimeText
+}
+
+// siweTimeText 解析得到的时间及其原始文本
+type siweTimeText struct {
+	t    time.Time
+	text string
+}
+
+// format 时间与解析时一致时返回原始文本，否则使用 RFC3339Nano 格式化
+func (tt siweTimeText) format(t time.Time) string {
+	if tt.text != "" && tt.t.Equal(t) {
+		return tt.text
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// SiweValidateOptions 校验SIWE消息的选项，零值字段不校验
+type SiweValidateOptions struct {
+	Domain  string
+	Nonce   string
+	ChainID int64
+	Time    time.Time // 校验有效期使用的时间，零值表示当前时间
+}
+
+// SiweVerifyOptions 验证SIWE签名的选项
+type SiweVerifyOptions struct {
+	SiweValidateOptions
+	// Verifier 不为nil时，EOA签名验证失败后通过 EIP-1271/EIP-6492 验证合约钱包签名
+	Verifier *SignatureVerifier
+}
+
+// NewSiweMessage 新建一个SIWE消息，自动生成 nonce，IssuedAt 为当前时间
+func NewSiweMessage(domain string, address common.Address, uri string, chainID int64) (*SiweMessage, error) {
+	nonce, err := GenerateSiweNonce()
+	if err != nil {
+		return nil, err
+	}
+	m := &SiweMessage{
+		Domain:   domain,
+		Address:  address,
+		URI:      uri,
+		Version:  siweVersion,
+		ChainID:  chainID,
+		Nonce:    nonce,
+		IssuedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	if err := m.validateFormat(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GenerateSiweNonce 生成一个随机的字母数字 nonce
+func GenerateSiweNonce() (string, error) {
+	max := big.NewInt(int64(len(siweNonceAlphabet)))
+	var sb strings.Builder
+	for i := 0; i < siweNonceLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to generate nonce")
+		}
+		sb.WriteByte(siweNonceAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+// String 生成用于签名的 EIP-4361 文本
+func (m *SiweMessage) String() string {
+	var sb strings.Builder
+	if m.Scheme != "" {
+		sb.WriteString(m.Scheme + "://")
+	}
+	sb.WriteString(m.Domain + siweHeaderSuffix + "\n")
+	sb.WriteString(m.Address.Hex() + "\n\n")
+	if m.Statement != "" {
+		sb.WriteString(m.Statement + "\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(siweURITag + m.URI + "\n")
+	sb.WriteString(siweVersionTag + m.Version + "\n")
+	sb.WriteString(siweChainIDTag + strconv.FormatInt(m.ChainID, 10) + "\n")
+	sb.WriteString(siweNonceTag + m.Nonce + "\n")
+	sb.WriteString(siweIssuedAtTag + m.issuedAtText.format(m.IssuedAt))
+	if m.ExpirationTime != nil {
+		sb.WriteString("\n" + siweExpirationTimeTag + m.expirationTimeText.format(*m.ExpirationTime))
+	}
+	if m.NotBefore != nil {
+		sb.WriteString("\n" + siweNotBeforeTag + m.notBeforeText.format(*m.NotBefore))
+	}
+	if m.RequestID != "" {
+		sb.WriteString("\n" + siweRequestIDTag + m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		sb.WriteString("\n" + siweResourcesTag)
+		for _, resource := range m.Resources {
+			sb.WriteString("\n" + siweResourcePrefix + resource)
+		}
+	}
+	return sb.String()
+}
+
+// ParseSiweMessage 解析 EIP-4361 文本，格式错误时返回 ErrSiweInvalidMessage
+func ParseSiweMessage(message string) (*SiweMessage, error) {
+	lines := strings.Split(message, "\n")
+	p := &siweParser{lines: lines}
+	m := &SiweMessage{}
+
+	header, ok := p.next()
+	if !ok || !strings.HasSuffix(header, siweHeaderSuffix) {
+		return nil, siweInvalid("missing header")
+	}
+	m.Domain = strings.TrimSuffix(header, siweHeaderSuffix)
+	if i := strings.Index(m.Domain, "://"); i >= 0 {
+		m.Scheme, m.Domain = m.Domain[:i], m.Domain[i+3:]
+	}
+
+	addressLine, _ := p.next()
+	if !common.IsHexAddress(addressLine) || common.HexToAddress(addressLine).Hex() != addressLine {
+		return nil, siweInvalid("address must be an EIP-55 checksum address")
+	}
+	m.Address = common.HexToAddress(addressLine)
+
+	if line, _ := p.next(); line != "" {
+		return nil, siweInvalid("expected empty line after address")
+	}
+	if line, _ := p.peek(); line != "" && !strings.HasPrefix(line, siweURITag) {
+		m.Statement = line
+		p.pos++
+	}
+	if line, _ := p.next(); line != "" {
+		return nil, siweInvalid("expected empty line before fields")
+	}
+
+	var err error
+	if m.URI, err = p.field(siweURITag); err != nil {
+		return nil, err
+	}
+	if m.Version, err = p.field(siweVersionTag); err != nil {
+		return nil, err
+	}
+	chainID, err := p.field(siweChainIDTag)
+	if err != nil {
+		return nil, err
+	}
+	if m.ChainID, err = strconv.ParseInt(chainID, 10, 64); err != nil {
+		return nil, siweInvalid("invalid chain id")
+	}
+	if m.Nonce, err = p.field(siweNonceTag); err != nil {
+		return nil, err
+	}
+	issuedAt, err := p.field(siweIssuedAtTag)
+	if err != nil {
+		return nil, err
+	}
+	if m.IssuedAt, err = time.Parse(time.RFC3339, issuedAt); err != nil {
+		return nil, siweInvalid("invalid issued at")
+	}
+	m.issuedAtText = siweTimeText{t: m.IssuedAt, text: issuedAt}
+	if m.ExpirationTime, m.expirationTimeText, err = p.optionalTime(siweExpirationTimeTag); err != nil {
+		return nil, err
+	}
+	if m.NotBefore, m.notBeforeText, err = p.optionalTime(siweNotBeforeTag); err != nil {
+		return nil, err
+	}
+	if line, ok := p.peek(); ok && strings.HasPrefix(line, siweRequestIDTag) {
+		m.RequestID = strings.TrimPrefix(line, siweRequestIDTag)
+		p.pos++
+	}
+	if line, ok := p.peek(); ok && line == siweResourcesTag {
+		p.pos++
+		for {
+			line, ok := p.peek()
+			if !ok || !strings.HasPrefix(line, siweResourcePrefix) {
+				break
+			}
+			m.Resources = append(m.Resources, strings.TrimPrefix(line, siweResourcePrefix))
+			p.pos++
+		}
+	}
+	if line, ok := p.peek(); ok {
+		return nil, siweInvalid("unexpected line " + strconv.Quote(line))
+	}
+
+	if err := m.validateFormat(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Validate 校验消息格式、domain、nonce、chain id 以及有效期
+func (m *SiweMessage) Validate(opts SiweValidateOptions) error {
+	if err := m.validateFormat(); err != nil {
+		return err
+	}
+	if opts.Domain != "" && opts.Domain != m.Domain {
+		return errors.Wrapf(ErrSiweDomainMismatch, "expected %s, got %s", opts.Domain, m.Domain)
+	}
+	if opts.Nonce != "" && opts.Nonce != m.Nonce {
+		return errors.Wrapf(ErrSiweNonceMismatch, "expected %s, got %s", opts.Nonce, m.Nonce)
+	}
+	if opts.ChainID != 0 && opts.ChainID != m.ChainID {
+		return errors.Wrapf(ErrSiweChainIDMismatch, "expected %d, got %d", opts.ChainID, m.ChainID)
+	}
+
+	now := opts.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if m.ExpirationTime != nil && !now.Before(*m.ExpirationTime) {
+		return errors.Wrapf(ErrSiweExpired, "expired at %s", m.ExpirationTime.Format(time.RFC3339))
+	}
+	if m.NotBefore != nil && now.Before(*m.NotBefore) {
+		return errors.Wrapf(ErrSiweNotYetValid, "valid from %s", m.NotBefore.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Verify 校验消息并验证签名。先按 EIP-191 恢复 EOA 签名者，
+// 不一致且 opts.Verifier 不为nil时再通过 EIP-1271/EIP-6492 验证合约钱包签名。
+// 签名不匹配时返回 ErrSignatureVerificationFailed。
+func (m *SiweMessage) Verify(signature []byte, opts SiweVerifyOptions) error {
+	return m.verify([]byte(m.String()), signature, opts)
+}
+
+// VerifySiweMessage 解析并验证SIWE消息的签名，成功时返回解析后的消息。签名按原始文本验证。
+func VerifySiweMessage(message string, signature []byte, opts SiweVerifyOptions) (*SiweMessage, error) {
+	m, err := ParseSiweMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.verify([]byte(message), signature, opts); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *SiweMessage) verify(message, signature []byte, opts SiweVerifyOptions) error {
+	if err := m.Validate(opts.SiweValidateOptions); err != nil {
+		return err
+	}
+
+	_, err := VerifyHashSignature(m.Address, HashPersonalMessage(message).Bytes(), signature)
+	if err == nil || opts.Verifier == nil {
+		return err
+	}
+
+	valid, err := opts.Verifier.VerifyMessage(m.Address, message, signature)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.Wrapf(ErrSignatureVerificationFailed, "invalid signature for %s", m.Address.Hex())
+	}
+	return nil
+}
+
+// validateFormat 校验各字段是否满足 EIP-4361 的格式要求
+func (m *SiweMessage) validateFormat() error {
+	if m.Domain == "" || strings.ContainsAny(m.Domain, " \n/") {
+		return siweInvalid("invalid domain")
+	}
+	if strings.Contains(m.Statement, "\n") {
+		return siweInvalid("statement must not contain newlines")
+	}
+	if u, err := url.Parse(m.URI); err != nil || u.Scheme == "" {
+		return siweInvalid("invalid uri")
+	}
+	if m.Version != siweVersion {
+		return siweInvalid("unsupported version " + strconv.Quote(m.Version))
+	}
+	if len(m.Nonce) < siweNonceMinLength || strings.Trim(m.Nonce, siweNonceAlphabet) != "" {
+		return siweInvalid("nonce must be at least 8 alphanumeric characters")
+	}
+	if m.IssuedAt.IsZero() {
+		return siweInvalid("missing issued at")
+	}
+	for _, resource := range m.Resources {
+		if u, err := url.Parse(resource); err != nil || u.Scheme == "" {
+			return siweInvalid("invalid resource " + strconv.Quote(resource))
+		}
+	}
+	return nil
+}
+
+func siweInvalid(reason string) error {
+	return errors.Wrap(ErrSiweInvalidMessage, reason)
+}
+
+// siweParser 按行顺序解析SIWE消息
+type siweParser struct {
+	lines []string
+	pos   int
+}
+
+func (p *siweParser) peek() (string, bool) {
+	if p.pos >= len(p.lines) {
+		return "", false
+	}
+	return p.lines[p.pos], true
+}
+
+func (p *siweParser) next() (string, bool) {
+	line, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+	return line, ok
+}
+
+func (p *siweParser) field(tag string) (string, error) {
+	line, ok := p.next()
+	if !ok || !strings.HasPrefix(line, tag) {
+		return "", siweInvalid("missing " + strings.TrimSuffix(tag, ": "))
+	}
+	return strings.TrimPrefix(line, tag), nil
+}
+
+func (p *siweParser) optionalTime(tag string) (*time.Time, siweTimeText, error) {
+	line, ok := p.peek()
+	if !ok || !strings.HasPrefix(line, tag) {
+		return nil, siweTimeText{}, nil
+	}
+	p.pos++
+	text := strings.TrimPrefix(line, tag)
+	t, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		return nil, siweTimeText{}, siweInvalid("invalid " + strings.TrimSuffix(tag, ": "))
+	}
+	return &t, siweTimeText{t: t, text: text}, nil
+}
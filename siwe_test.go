@@ -0,0 +1,217 @@
+package etherkit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const siweTestMessage = `service.org wants you to sign in with your Ethereum account:
+0xe5A12547fe4E872D192E3eCecb76F2Ce1aeA4946
+
+I accept the ServiceOrg Terms of Service: https://service.org/tos
+
+URI: https://service.org/login
+Version: 1
+Chain ID: 1
+Nonce: 32891757
+Issued At: 2021-09-30T16:25:24.000Z
+Expiration Time: 2021-10-30T16:25:24Z
+Resources:
+- ipfs://Qme7ss3ARVgxv6rXqVPiikMJ8u2NLgmgszg13pYrDKEoiu
+- https://example.com/my-web2-claim.json`
+
+func TestParseSiweMessage(t *testing.T) {
+	m, err := ParseSiweMessage(siweTestMessage)
+	if err != nil {
+		t.Fatalf("ParseSiweMessage() failed: %v", err)
+	}
+
+	if m.Domain != "service.org" {
+		t.Errorf("Domain = %s, expected service.org", m.Domain)
+	}
+	if m.Address != common.HexToAddress("0xe5A12547fe4E872D192E3eCecb76F2Ce1aeA4946") {
+		t.Errorf("Address = %s", m.Address.Hex())
+	}
+	if m.Statement != "I accept the ServiceOrg Terms of Service: https://service.org/tos" {
+		t.Errorf("Statement = %q", m.Statement)
+	}
+	if m.URI != "https://service.org/login" || m.Version != "1" || m.ChainID != 1 || m.Nonce != "32891757" {
+		t.Errorf("Unexpected fields: %+v", m)
+	}
+	if !m.IssuedAt.Equal(time.Date(2021, 9, 30, 16, 25, 24, 0, time.UTC)) {
+		t.Errorf("IssuedAt = %s", m.IssuedAt)
+	}
+	if m.ExpirationTime == nil || !m.ExpirationTime.Equal(time.Date(2021, 10, 30, 16, 25, 24, 0, time.UTC)) {
+		t.Errorf("ExpirationTime = %v", m.ExpirationTime)
+	}
+	if m.NotBefore != nil {
+		t.Errorf("NotBefore = %v, expected nil", m.NotBefore)
+	}
+	if len(m.Resources) != 2 {
+		t.Errorf("Resources = %v", m.Resources)
+	}
+}
+
+func TestParseSiweMessageInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+	}{
+		{"Empty", ""},
+		{"Missing header", strings.Replace(siweTestMessage, " wants you to sign in", " wants to sign in", 1)},
+		{"Non-checksum address", strings.Replace(siweTestMessage, "0xe5A12547fe4E872D192E3eCecb76F2Ce1aeA4946", "0xe5a12547fe4e872d192e3ececb76f2ce1aea4946", 1)},
+		{"Short nonce", strings.Replace(siweTestMessage, "Nonce: 32891757", "Nonce: 1234", 1)},
+		{"Bad version", strings.Replace(siweTestMessage, "Version: 1", "Version: 2", 1)},
+		{"Bad chain id", strings.Replace(siweTestMessage, "Chain ID: 1", "Chain ID: one", 1)},
+		{"Bad issued at", strings.Replace(siweTestMessage, "2021-09-30T16:25:24.000Z", "yesterday", 1)},
+		{"Missing URI", strings.Replace(siweTestMessage, "URI: https://service.org/login\n", "", 1)},
+		{"Trailing garbage", siweTestMessage + "\nextra"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSiweMessage(tt.message); !errors.Is(err, ErrSiweInvalidMessage) {
+				t.Errorf("Expected ErrSiweInvalidMessage, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSiweMessageRoundTrip(t *testing.T) {
+	signer, err := NewSigner()
+	if err != nil {
+		t.Fatalf("NewSigner() failed: %v", err)
+	}
+
+	m, err := NewSiweMessage("localhost:3000", signer.GetAddress(), "http://localhost:3000/login", MainnetChainID)
+	if err != nil {
+		t.Fatalf("NewSiweMessage() failed: %v", err)
+	}
+	expiration := m.IssuedAt.Add(time.Hour)
+	m.Scheme = "http"
+	m.ExpirationTime = &expiration
+	m.RequestID = "request-1"
+
+	// 无 statement 的消息也能正确解析
+	parsed, err := ParseSiweMessage(m.String())
+	if err != nil {
+		t.Fatalf("ParseSiweMessage() failed: %v", err)
+	}
+	if parsed.String() != m.String() {
+		t.Errorf("Round trip mismatch:\n%s\n---\n%s", parsed.String(), m.String())
+	}
+	if parsed.Scheme != "http" || parsed.Domain != "localhost:3000" || parsed.Statement != "" {
+		t.Errorf("Unexpected fields: %+v", parsed)
+	}
+}
+
+func TestSiweMessageVerifyFractionalSeconds(t *testing.T) {
+	signer, err := NewSigner()
+	if err != nil {
+		t.Fatalf("NewSigner() failed: %v", err)
+	}
+
+	// 钱包生成的消息常带毫秒，如 .000Z 和 .120Z，String() 必须与原文一致
+	message := strings.Replace(siweTestMessage, "0xe5A12547fe4E872D192E3eCecb76F2Ce1aeA4946", signer.GetAddress().Hex(), 1)
+	message = strings.Replace(message, "Expiration Time: 2021-10-30T16:25:24Z", "Expiration Time: 2021-10-30T16:25:24.120Z\nNot Before: 2021-09-30T16:25:24.500+08:00", 1)
+	signature, err := signer.SignPersonalMessage([]byte(message))
+	if err != nil {
+		t.Fatalf("SignPersonalMessage() failed: %v", err)
+	}
+
+	m, err := ParseSiweMessage(message)
+	if err != nil {
+		t.Fatalf("ParseSiweMessage() failed: %v", err)
+	}
+	if m.String() != message {
+		t.Errorf("String() mismatch:\n%s\n---\n%s", m.String(), message)
+	}
+	opts := SiweVerifyOptions{SiweValidateOptions: SiweValidateOptions{Time: m.IssuedAt}}
+	if err := m.Verify(signature, opts); err != nil {
+		t.Errorf("Verify() failed: %v", err)
+	}
+
+	// 修改时间后按 RFC3339Nano 重新格式化
+	issuedAt := m.IssuedAt.Add(1500 * time.Millisecond)
+	m.IssuedAt = issuedAt
+	if !strings.Contains(m.String(), "Issued At: 2021-09-30T16:25:25.5Z") {
+		t.Errorf("Modified IssuedAt not formatted: %s", m.String())
+	}
+}
+
+func TestSiweMessageVerify(t *testing.T) {
+	signer, err := NewSigner()
+	if err != nil {
+		t.Fatalf("NewSigner() failed: %v", err)
+	}
+
+	m, err := NewSiweMessage("example.com", signer.GetAddress(), "https://example.com", MainnetChainID)
+	if err != nil {
+		t.Fatalf("NewSiweMessage() failed: %v", err)
+	}
+	m.Statement = "Sign in to Example"
+	notBefore := m.IssuedAt.Add(-time.Minute)
+	expiration := m.IssuedAt.Add(time.Hour)
+	m.NotBefore = &notBefore
+	m.ExpirationTime = &expiration
+
+	message := m.String()
+	signature, err := signer.SignPersonalMessage([]byte(message))
+	if err != nil {
+		t.Fatalf("SignPersonalMessage() failed: %v", err)
+	}
+
+	opts := SiweVerifyOptions{SiweValidateOptions: SiweValidateOptions{
+		Domain:  "example.com",
+		Nonce:   m.Nonce,
+		ChainID: MainnetChainID,
+		Time:    m.IssuedAt,
+	}}
+
+	verified, err := VerifySiweMessage(message, signature, opts)
+	if err != nil {
+		t.Fatalf("VerifySiweMessage() failed: %v", err)
+	}
+	if verified.Address != signer.GetAddress() {
+		t.Errorf("Address = %s, expected %s", verified.Address.Hex(), signer.GetAddress().Hex())
+	}
+
+	tests := []struct {
+		name   string
+		modify func(o *SiweVerifyOptions)
+		target error
+	}{
+		{"Domain mismatch", func(o *SiweVerifyOptions) { o.Domain = "evil.com" }, ErrSiweDomainMismatch},
+		{"Nonce mismatch", func(o *SiweVerifyOptions) { o.Nonce = "otherNonce123" }, ErrSiweNonceMismatch},
+		{"Chain ID mismatch", func(o *SiweVerifyOptions) { o.ChainID = PolygonChainID }, ErrSiweChainIDMismatch},
+		{"Expired", func(o *SiweVerifyOptions) { o.Time = expiration }, ErrSiweExpired},
+		{"Not yet valid", func(o *SiweVerifyOptions) { o.Time = notBefore.Add(-time.Second) }, ErrSiweNotYetValid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := opts
+			tt.modify(&o)
+			if err := m.Verify(signature, o); !errors.Is(err, tt.target) {
+				t.Errorf("Expected %v, got %v", tt.target, err)
+			}
+		})
+	}
+
+	// 其他账户的签名
+	other, err := NewSigner()
+	if err != nil {
+		t.Fatalf("NewSigner() failed: %v", err)
+	}
+	otherSignature, err := other.SignPersonalMessage([]byte(message))
+	if err != nil {
+		t.Fatalf("SignPersonalMessage() failed: %v", err)
+	}
+	if err := m.Verify(otherSignature, opts); !errors.Is(err, ErrSignatureVerificationFailed) {
+		t.Errorf("Expected ErrSignatureVerificationFailed, got %v", err)
+	}
+}
@@ -23,6 +23,18 @@ func NewTx(to common.Address, nonce, gasLimit uint64, gasPrice, value *big.Int,
 	}), nil
 }
 
+// NewContractCreationTx 新建一个创建合约的tx（to为空），data为合约的 init code
+func NewContractCreationTx(nonce, gasLimit uint64, gasPrice, value *big.Int, data []byte) (*types.Transaction, error) {
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		To:       nil,
+		Value:    value,
+		Gas:      gasLimit,
+		GasPrice: gasPrice,
+		Data:     data,
+	}), nil
+}
+
 // NewTxWithHexData 基于hexData构建一个Tx
 func NewTxWithHexData(to common.Address, nonce, gasLimit uint64, gasPrice, value *big.Int, hexData string) (*types.Transaction, error) {
 	data, err := hex.DecodeString(hexData)
@@ -0,0 +1,198 @@
+package etherkit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+//############ Typed Contract Call ############
+
+// CallContractAs 调用合约的只读方法，并将返回值解码为 T。
+//
+// 只有一个返回值时 T 对应该返回值本身（tuple 对应结构体）；有多个返回值时 T 必须是结构体，
+// 按 `abi:"name"` 标签或驼峰字段名匹配返回值名，未命名的返回值按位置匹配。
+// tuple 的组件同样按标签或字段名映射，数组和切片逐元素递归解码；类型不匹配时返回 ErrReturnTypeMismatch
+func CallContractAs[T any](w EtherWallet, contractAddress common.Address, contractAbi abi.ABI, method string, params ...interface{}) (T, error) {
+	values, err := w.CallContract(contractAddress, contractAbi, method, params...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return convertOutputs[T](contractAbi, method, values)
+}
+
+// CallAs 与 CallContractAs 相同，通过 BoundContract 调用
+func CallAs[T any](ctx context.Context, c *BoundContract, method string, params ...interface{}) (T, error) {
+	values, err := c.Call(ctx, method, params...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return convertOutputs[T](c.abi, method, values)
+}
+
+// UnpackAs 将 eth_call 的原始返回数据解码为 T，规则同 CallContractAs
+func UnpackAs[T any](contractAbi abi.ABI, method string, data []byte) (T, error) {
+	var zero T
+	if _, ok := contractAbi.Methods[method]; !ok {
+		return zero, errors.Wrap(ErrMethodNotFound, method)
+	}
+	values, err := contractAbi.Unpack(method, data)
+	if err != nil {
+		return zero, err
+	}
+	return convertOutputs[T](contractAbi, method, values)
+}
+
+func convertOutputs[T any](contractAbi abi.ABI, method string, values []interface{}) (T, error) {
+	var out T
+	m, ok := contractAbi.Methods[method]
+	if !ok {
+		return out, errors.Wrap(ErrMethodNotFound, method)
+	}
+	outputs := m.Outputs
+	if len(outputs) == 0 || len(outputs) != len(values) {
+		return out, errors.Wrapf(ErrReturnTypeMismatch, "%s returns %d values", method, len(values))
+	}
+
+	dst := reflect.ValueOf(&out).Elem()
+	if len(outputs) == 1 && !isStructTarget(dst.Type()) || len(outputs) == 1 && outputs[0].Type.T == abi.TupleTy {
+		return out, assignABIValue(dst, reflect.ValueOf(values[0]), outputs[0].Type, method)
+	}
+
+	// 多个返回值（或单个非 tuple 返回值解码到结构体）视为一个 tuple
+	dst = derefAlloc(dst)
+	if dst.Kind() != reflect.Struct {
+		return out, errors.Wrapf(ErrReturnTypeMismatch, "%s: %d return values cannot be decoded into %s", method, len(outputs), dst.Type())
+	}
+	for i, output := range outputs {
+		field, err := findABIField(dst, output.Name, i)
+		if err != nil {
+			return out, errors.Wrapf(err, "%s", method)
+		}
+		if err := assignABIValue(field, reflect.ValueOf(values[i]), output.Type, method+"."+outputName(output.Name, i)); err != nil {
+			return out, err
+		}
+	}
+	return out, nil
+}
+
+// assignABIValue 将 abi 解码得到的值 src 按类型 typ 赋值给 dst，path 用于错误信息
+func assignABIValue(dst, src reflect.Value, typ abi.Type, path string) error {
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return nil
+	}
+	if dst.Kind() == reflect.Ptr && src.Kind() != reflect.Ptr {
+		return assignABIValue(derefAlloc(dst), src, typ, path)
+	}
+
+	switch typ.T {
+	case abi.TupleTy:
+		dst = derefAlloc(dst)
+		if dst.Kind() != reflect.Struct {
+			return typeMismatch(path, typ, dst)
+		}
+		for i, name := range typ.TupleRawNames {
+			field, err := findABIField(dst, name, i)
+			if err != nil {
+				return errors.Wrapf(err, "%s", path)
+			}
+			if err := assignABIValue(field, src.Field(i), *typ.TupleElems[i], path+"."+name); err != nil {
+				return err
+			}
+		}
+		return nil
+	case abi.SliceTy, abi.ArrayTy:
+		switch dst.Kind() {
+		case reflect.Slice:
+			dst.Set(reflect.MakeSlice(dst.Type(), src.Len(), src.Len()))
+		case reflect.Array:
+			if dst.Len() != src.Len() {
+				return errors.Wrapf(ErrReturnTypeMismatch, "%s: cannot decode %d elements into %s", path, src.Len(), dst.Type())
+			}
+		default:
+			return typeMismatch(path, typ, dst)
+		}
+		for i := 0; i < src.Len(); i++ {
+			if err := assignABIValue(dst.Index(i), src.Index(i), *typ.Elem, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case abi.FixedBytesTy:
+		if dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8 {
+			b := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+			reflect.Copy(b, src)
+			dst.Set(b)
+			return nil
+		}
+	}
+
+	// 同种类的命名类型之间允许转换，如 [32]byte 与 common.Hash
+	if src.Kind() == dst.Kind() && src.Type().ConvertibleTo(dst.Type()) {
+		dst.Set(src.Convert(dst.Type()))
+		return nil
+	}
+	return typeMismatch(path, typ, dst)
+}
+
+// findABIField 按 abi 标签、驼峰字段名的顺序查找结构体字段；未命名的返回值按位置匹配
+func findABIField(dst reflect.Value, name string, index int) (reflect.Value, error) {
+	typ := dst.Type()
+	if name != "" {
+		for i := 0; i < typ.NumField(); i++ {
+			// 未导出字段无法赋值，即使标签匹配也跳过
+			if tag, ok := typ.Field(i).Tag.Lookup("abi"); ok && tag == name && typ.Field(i).IsExported() {
+				return dst.Field(i), nil
+			}
+		}
+		if field, ok := typ.FieldByName(abi.ToCamelCase(name)); ok && field.IsExported() {
+			return dst.FieldByIndex(field.Index), nil
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			if typ.Field(i).IsExported() && strings.EqualFold(typ.Field(i).Name, abi.ToCamelCase(name)) {
+				return dst.Field(i), nil
+			}
+		}
+	} else if index < typ.NumField() && typ.Field(index).IsExported() {
+		return dst.Field(index), nil
+	}
+	return reflect.Value{}, errors.Wrapf(ErrReturnTypeMismatch, "no field for %q in %s", outputName(name, index), typ)
+}
+
+// derefAlloc 解引用指针，指针为 nil 时分配新值
+func derefAlloc(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func isStructTarget(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	// *big.Int 等常见的值类型不视为多返回值的容器
+	return t.Kind() == reflect.Struct && t.PkgPath() != "math/big"
+}
+
+func outputName(name string, index int) string {
+	if name == "" {
+		return fmt.Sprintf("output%d", index)
+	}
+	return name
+}
+
+func typeMismatch(path string, typ abi.Type, dst reflect.Value) error {
+	return errors.Wrapf(ErrReturnTypeMismatch, "%s: cannot decode %s into %s", path, typ.String(), dst.Type())
+}
@@ -0,0 +1,115 @@
+package etherkit
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const typedCallTestABI = `[
+	{"name":"getReserves","type":"function","stateMutability":"view","inputs":[],"outputs":[
+		{"name":"_reserve0","type":"uint112"},{"name":"_reserve1","type":"uint112"},{"name":"_blockTimestampLast","type":"uint32"}]},
+	{"name":"totalSupply","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"name":"domainSeparator","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"bytes32"}]},
+	{"name":"getPool","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"pool","type":"tuple","components":[
+		{"name":"owner","type":"address"},
+		{"name":"fee_bps","type":"uint16"},
+		{"name":"tokens","type":"tuple[]","components":[{"name":"token","type":"address"},{"name":"weights","type":"uint256[2]"}]}]}]}
+]`
+
+type typedCallReserves struct {
+	Reserve0  *big.Int `abi:"_reserve0"`
+	Reserve1  *big.Int `abi:"_reserve1"`
+	Timestamp uint32   `abi:"_blockTimestampLast"`
+}
+
+type typedCallToken struct {
+	Token   common.Address
+	Weights []*big.Int
+}
+
+type typedCallPool struct {
+	Owner  common.Address
+	FeeBps uint16
+	Tokens []typedCallToken
+}
+
+func TestUnpackAs(t *testing.T) {
+	contractAbi, err := GetABI(typedCallTestABI)
+	if err != nil {
+		t.Fatalf("GetABI() failed: %v", err)
+	}
+	pack := func(method string, values ...interface{}) []byte {
+		data, err := contractAbi.Methods[method].Outputs.Pack(values...)
+		if err != nil {
+			t.Fatalf("Pack(%s) failed: %v", method, err)
+		}
+		return data
+	}
+
+	reserves, err := UnpackAs[typedCallReserves](contractAbi, "getReserves", pack("getReserves", big.NewInt(10), big.NewInt(20), uint32(30)))
+	if err != nil || reserves.Reserve0.Int64() != 10 || reserves.Reserve1.Int64() != 20 || reserves.Timestamp != 30 {
+		t.Errorf("UnpackAs[reserves]() = %+v, %v", reserves, err)
+	}
+
+	supply, err := UnpackAs[*big.Int](contractAbi, "totalSupply", pack("totalSupply", big.NewInt(1000)))
+	if err != nil || supply.Int64() != 1000 {
+		t.Errorf("UnpackAs[*big.Int]() = %v, %v", supply, err)
+	}
+
+	hash := common.HexToHash("0x1234")
+	separator, err := UnpackAs[common.Hash](contractAbi, "domainSeparator", pack("domainSeparator", [32]byte(hash)))
+	if err != nil || separator != hash {
+		t.Errorf("UnpackAs[common.Hash]() = %s, %v", separator.Hex(), err)
+	}
+
+	pool := struct {
+		Owner  common.Address
+		FeeBps uint16 `json:"fee_bps"`
+		Tokens []struct {
+			Token   common.Address
+			Weights [2]*big.Int
+		}
+	}{Owner: guardTrusted, FeeBps: 30}
+	pool.Tokens = append(pool.Tokens, struct {
+		Token   common.Address
+		Weights [2]*big.Int
+	}{guardUnrelated, [2]*big.Int{big.NewInt(1), big.NewInt(2)}})
+	decoded, err := UnpackAs[*typedCallPool](contractAbi, "getPool", pack("getPool", pool))
+	if err != nil {
+		t.Fatalf("UnpackAs[*pool]() failed: %v", err)
+	}
+	if decoded.Owner != guardTrusted || decoded.FeeBps != 30 || len(decoded.Tokens) != 1 ||
+		decoded.Tokens[0].Token != guardUnrelated || decoded.Tokens[0].Weights[1].Int64() != 2 {
+		t.Errorf("Unexpected pool: %+v", decoded)
+	}
+
+	// 形状不匹配
+	if _, err := UnpackAs[uint64](contractAbi, "getReserves", pack("getReserves", big.NewInt(1), big.NewInt(2), uint32(3))); !errors.Is(err, ErrReturnTypeMismatch) {
+		t.Errorf("Expected ErrReturnTypeMismatch for multiple outputs, got %v", err)
+	}
+	if _, err := UnpackAs[struct{ Reserve0 *big.Int }](contractAbi, "getReserves", pack("getReserves", big.NewInt(1), big.NewInt(2), uint32(3))); !errors.Is(err, ErrReturnTypeMismatch) {
+		t.Errorf("Expected ErrReturnTypeMismatch for missing field, got %v", err)
+	}
+	amountAbi, err := GetABI(`[{"name":"balance","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"amount","type":"uint256"}]}]`)
+	if err != nil {
+		t.Fatalf("GetABI() failed: %v", err)
+	}
+	amountData, err := amountAbi.Methods["balance"].Outputs.Pack(big.NewInt(1))
+	if err != nil {
+		t.Fatalf("Pack() failed: %v", err)
+	}
+	if _, err := UnpackAs[struct {
+		amount *big.Int `abi:"amount"`
+	}](amountAbi, "balance", amountData); !errors.Is(err, ErrReturnTypeMismatch) {
+		t.Errorf("Expected ErrReturnTypeMismatch for unexported tagged field, got %v", err)
+	}
+	if _, err := UnpackAs[string](contractAbi, "totalSupply", pack("totalSupply", big.NewInt(1))); !errors.Is(err, ErrReturnTypeMismatch) {
+		t.Errorf("Expected ErrReturnTypeMismatch for wrong type, got %v", err)
+	}
+	if _, err := UnpackAs[*big.Int](contractAbi, "balanceOf", nil); !errors.Is(err, ErrMethodNotFound) {
+		t.Errorf("Expected ErrMethodNotFound, got %v", err)
+	}
+}
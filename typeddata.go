@@ -0,0 +1,289 @@
+package etherkit
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+)
+
+//############ EIP-712 ############
+
+// TypedData EIP-712 结构化数据（domain、types、primaryType、message）
+type TypedData = apitypes.TypedData
+
+// TypedDataDomain EIP-712 的 domain
+type TypedDataDomain = apitypes.TypedDataDomain
+
+// TypedDataTypes EIP-712 的类型定义，key为类型名
+type TypedDataTypes = apitypes.Types
+
+// TypedDataField EIP-712 类型中的一个字段
+type TypedDataField = apitypes.Type
+
+// eip712DomainType EIP712Domain 的类型名
+const eip712DomainType = "EIP712Domain"
+
+// NewTypedData 构建EIP-712结构化数据。
+// message 可以是 map[string]interface{}，也可以是Go结构体（字段名取 eip712 tag，其次是 json tag，最后是字段名）。
+// 如果 types 中没有 EIP712Domain，会根据 domain 中已设置的字段自动补全。
+func NewTypedData(domain TypedDataDomain, types TypedDataTypes, primaryType string, message interface{}) (*TypedData, error) {
+	if _, ok := types[primaryType]; !ok {
+		return nil, errors.Errorf("primary type %q is not defined in types", primaryType)
+	}
+
+	msg, err := toTypedDataMessage(message)
+	if err != nil {
+		return nil, err
+	}
+
+	allTypes := make(TypedDataTypes, len(types)+1)
+	for name, fields := range types {
+		allTypes[name] = fields
+	}
+	if _, ok := allTypes[eip712DomainType]; !ok {
+		allTypes[eip712DomainType] = typedDataDomainFields(domain)
+	}
+
+	return &TypedData{
+		Types:       allTypes,
+		PrimaryType: primaryType,
+		Domain:      domain,
+		Message:     msg,
+	}, nil
+}
+
+// ParseTypedDataJSON 从标准的 eth_signTypedData_v4 JSON 数据中解析EIP-712结构化数据
+func ParseTypedDataJSON(data []byte) (*TypedData, error) {
+	var raw struct {
+		Types       TypedDataTypes         `json:"types"`
+		PrimaryType string                 `json:"primaryType"`
+		Domain      TypedDataDomain        `json:"domain"`
+		Message     map[string]interface{} `json:"message"`
+	}
+
+	// 使用 json.Number 避免大整数精度丢失
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, errors.Wrap(err, "failed to decode typed data json")
+	}
+
+	return NewTypedData(raw.Domain, raw.Types, raw.PrimaryType, raw.Message)
+}
+
+// TypedDataDomainSeparator 计算 domain separator
+func TypedDataDomainSeparator(typedData *TypedData) (common.Hash, error) {
+	hash, err := typedData.HashStruct(eip712DomainType, typedData.Domain.Map())
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "failed to hash typed data domain")
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// TypedDataStructHash 计算 primaryType 对应 message 的 hashStruct
+func TypedDataStructHash(typedData *TypedData) (common.Hash, error) {
+	hash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "failed to hash typed data message")
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// TypedDataHash 计算用于签名的摘要：keccak256("\x19\x01" ‖ domainSeparator ‖ hashStruct(message))
+func TypedDataHash(typedData *TypedData) (common.Hash, error) {
+	domainSeparator, err := TypedDataDomainSeparator(typedData)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	structHash, err := TypedDataStructHash(typedData)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator.Bytes(), structHash.Bytes()), nil
+}
+
+// SignTypedData 使用私钥对EIP-712结构化数据签名，返回65字节签名（V为27/28，与 eth_signTypedData_v4 一致）
+func SignTypedData(privateKey *ecdsa.PrivateKey, typedData *TypedData) ([]byte, error) {
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := crypto.Sign(hash.Bytes(), privateKey)
+	if err != nil {
+		return nil, errors.Wrap(ErrSignatureFailed, err.Error())
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
+
+// RecoverTypedDataSigner 从EIP-712签名中恢复签名者地址
+func RecoverTypedDataSigner(typedData *TypedData, signature []byte) (common.Address, error) {
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return RecoverAddress(hash.Bytes(), signature)
+}
+
+// VerifyTypedDataSignature 验证EIP-712签名是否由 address 签出
+func VerifyTypedDataSignature(address common.Address, typedData *TypedData, signature []byte) bool {
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return false
+	}
+	_, err = VerifyHashSignature(address, hash.Bytes(), signature)
+	return err == nil
+}
+
+// typedDataDomainFields 根据domain中已设置的字段生成 EIP712Domain 类型
+func typedDataDomainFields(domain TypedDataDomain) []TypedDataField {
+	var fields []TypedDataField
+	if len(domain.Name) > 0 {
+		fields = append(fields, TypedDataField{Name: "name", Type: "string"})
+	}
+	if len(domain.Version) > 0 {
+		fields = append(fields, TypedDataField{Name: "version", Type: "string"})
+	}
+	if domain.ChainId != nil {
+		fields = append(fields, TypedDataField{Name: "chainId", Type: "uint256"})
+	}
+	if len(domain.VerifyingContract) > 0 {
+		fields = append(fields, TypedDataField{Name: "verifyingContract", Type: "address"})
+	}
+	if len(domain.Salt) > 0 {
+		fields = append(fields, TypedDataField{Name: "salt", Type: "bytes32"})
+	}
+	return fields
+}
+
+// toTypedDataMessage 将message转换为 apitypes 能识别的 map
+func toTypedDataMessage(message interface{}) (map[string]interface{}, error) {
+	if message == nil {
+		return nil, errors.New("typed data message is nil")
+	}
+	value, err := toTypedDataValue(reflect.ValueOf(message))
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("typed data message must be a struct or map, got %T", message)
+	}
+	return msg, nil
+}
+
+var (
+	bigIntType  = reflect.TypeOf(big.Int{})
+	addressType = reflect.TypeOf(common.Address{})
+	jsonNumType = reflect.TypeOf(json.Number(""))
+)
+
+// toTypedDataValue 递归转换Go值：整数转为*big.Int，地址转为十六进制字符串，定长字节数组转为[]byte，结构体转为map
+func toTypedDataValue(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, errors.New("typed data value is nil")
+	}
+
+	switch v.Type() {
+	case bigIntType:
+		b := v.Interface().(big.Int)
+		return new(big.Int).Set(&b), nil
+	case addressType:
+		return v.Interface().(common.Address).Hex(), nil
+	case jsonNumType:
+		return v.String(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, errors.New("typed data value is nil")
+		}
+		return toTypedDataValue(v.Elem())
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(v.Uint()), nil
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return b, nil
+		}
+		return toTypedDataSlice(v)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes(), nil
+		}
+		return toTypedDataSlice(v)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, errors.Errorf("unsupported typed data map key type %s", v.Type().Key())
+		}
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			item, err := toTypedDataValue(iter.Value())
+			if err != nil {
+				return nil, errors.Wrapf(err, "field %q", iter.Key().String())
+			}
+			m[iter.Key().String()] = item
+		}
+		return m, nil
+	case reflect.Struct:
+		m := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := typedDataFieldName(field)
+			if name == "-" {
+				continue
+			}
+			item, err := toTypedDataValue(v.Field(i))
+			if err != nil {
+				return nil, errors.Wrapf(err, "field %q", name)
+			}
+			m[name] = item
+		}
+		return m, nil
+	}
+	return nil, errors.Errorf("unsupported typed data value type %s", v.Type())
+}
+
+func toTypedDataSlice(v reflect.Value) ([]interface{}, error) {
+	items := make([]interface{}, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		item, err := toTypedDataValue(v.Index(i))
+		if err != nil {
+			return nil, errors.Wrapf(err, "index %d", i)
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+// typedDataFieldName 结构体字段在EIP-712中的名称
+func typedDataFieldName(field reflect.StructField) string {
+	for _, key := range []string{"eip712", "json"} {
+		if tag, ok := field.Tag.Lookup(key); ok {
+			if name := strings.Split(tag, ",")[0]; name != "" {
+				return name
+			}
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,229 @@
+package etherkit
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// EIP-712 规范中的 Mail 示例
+const eip712MailJSON = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+const (
+	eip712MailDomainSeparator = "0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f"
+	eip712MailStructHash      = "0xc52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e"
+	eip712MailHash            = "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"
+	eip712MailSignature       = "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c"
+)
+
+type eip712Person struct {
+	Name   string         `json:"name"`
+	Wallet common.Address `json:"wallet"`
+}
+
+type eip712Mail struct {
+	From     eip712Person `json:"from"`
+	To       eip712Person `json:"to"`
+	Contents string       `json:"contents"`
+}
+
+func TestParseTypedDataJSON(t *testing.T) {
+	typedData, err := ParseTypedDataJSON([]byte(eip712MailJSON))
+	if err != nil {
+		t.Fatalf("ParseTypedDataJSON() failed: %v", err)
+	}
+
+	domainSeparator, err := TypedDataDomainSeparator(typedData)
+	if err != nil {
+		t.Fatalf("TypedDataDomainSeparator() failed: %v", err)
+	}
+	if domainSeparator.Hex() != eip712MailDomainSeparator {
+		t.Errorf("domain separator = %s, expected %s", domainSeparator.Hex(), eip712MailDomainSeparator)
+	}
+
+	structHash, err := TypedDataStructHash(typedData)
+	if err != nil {
+		t.Fatalf("TypedDataStructHash() failed: %v", err)
+	}
+	if structHash.Hex() != eip712MailStructHash {
+		t.Errorf("struct hash = %s, expected %s", structHash.Hex(), eip712MailStructHash)
+	}
+
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		t.Fatalf("TypedDataHash() failed: %v", err)
+	}
+	if hash.Hex() != eip712MailHash {
+		t.Errorf("hash = %s, expected %s", hash.Hex(), eip712MailHash)
+	}
+}
+
+func TestNewTypedDataFromStruct(t *testing.T) {
+	domain := TypedDataDomain{
+		Name:              "Ether Mail",
+		Version:           "1",
+		ChainId:           math.NewHexOrDecimal256(1),
+		VerifyingContract: "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC",
+	}
+	types := TypedDataTypes{
+		"Person": {
+			{Name: "name", Type: "string"},
+			{Name: "wallet", Type: "address"},
+		},
+		"Mail": {
+			{Name: "from", Type: "Person"},
+			{Name: "to", Type: "Person"},
+			{Name: "contents", Type: "string"},
+		},
+	}
+	mail := eip712Mail{
+		From:     eip712Person{Name: "Cow", Wallet: common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")},
+		To:       eip712Person{Name: "Bob", Wallet: common.HexToAddress("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB")},
+		Contents: "Hello, Bob!",
+	}
+
+	// 未提供 EIP712Domain 时自动补全
+	typedData, err := NewTypedData(domain, types, "Mail", mail)
+	if err != nil {
+		t.Fatalf("NewTypedData() failed: %v", err)
+	}
+
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		t.Fatalf("TypedDataHash() failed: %v", err)
+	}
+	if hash.Hex() != eip712MailHash {
+		t.Errorf("hash = %s, expected %s", hash.Hex(), eip712MailHash)
+	}
+
+	// primaryType 不存在
+	if _, err := NewTypedData(domain, types, "Unknown", mail); err == nil {
+		t.Error("Expected error for unknown primary type")
+	}
+}
+
+func TestNewTypedDataIntegerFields(t *testing.T) {
+	domain := TypedDataDomain{Name: "Permit", ChainId: math.NewHexOrDecimal256(1)}
+	types := TypedDataTypes{
+		"Permit": {
+			{Name: "owner", Type: "address"},
+			{Name: "value", Type: "uint256"},
+			{Name: "nonce", Type: "uint256"},
+			{Name: "deadline", Type: "uint256"},
+		},
+	}
+	type permit struct {
+		Owner    common.Address `eip712:"owner"`
+		Value    *big.Int       `eip712:"value"`
+		Nonce    uint64         `eip712:"nonce"`
+		Deadline int64          `eip712:"deadline"`
+	}
+	owner := common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+
+	fromStruct, err := NewTypedData(domain, types, "Permit", permit{owner, GetMaxUint256(), 1, 1700000000})
+	if err != nil {
+		t.Fatalf("NewTypedData() failed: %v", err)
+	}
+	fromMap, err := NewTypedData(domain, types, "Permit", map[string]interface{}{
+		"owner":    owner.Hex(),
+		"value":    GetMaxUint256().String(),
+		"nonce":    "1",
+		"deadline": "1700000000",
+	})
+	if err != nil {
+		t.Fatalf("NewTypedData() failed: %v", err)
+	}
+
+	h1, err := TypedDataHash(fromStruct)
+	if err != nil {
+		t.Fatalf("TypedDataHash() failed: %v", err)
+	}
+	h2, err := TypedDataHash(fromMap)
+	if err != nil {
+		t.Fatalf("TypedDataHash() failed: %v", err)
+	}
+	if h1 != h2 {
+		t.Errorf("struct hash %s != map hash %s", h1.Hex(), h2.Hex())
+	}
+}
+
+func TestSignTypedData(t *testing.T) {
+	// EIP-712 规范示例使用的私钥为 keccak256("cow")
+	pk, err := crypto.ToECDSA(crypto.Keccak256([]byte("cow")))
+	if err != nil {
+		t.Fatalf("crypto.ToECDSA() failed: %v", err)
+	}
+	signer, err := NewSignerFromPrivateKey(pk)
+	if err != nil {
+		t.Fatalf("NewSignerFromPrivateKey() failed: %v", err)
+	}
+
+	typedData, err := ParseTypedDataJSON([]byte(eip712MailJSON))
+	if err != nil {
+		t.Fatalf("ParseTypedDataJSON() failed: %v", err)
+	}
+
+	signature, err := signer.SignTypedData(typedData)
+	if err != nil {
+		t.Fatalf("SignTypedData() failed: %v", err)
+	}
+	if hexutil.Encode(signature) != eip712MailSignature {
+		t.Errorf("signature = %s, expected %s", hexutil.Encode(signature), eip712MailSignature)
+	}
+
+	recovered, err := RecoverTypedDataSigner(typedData, signature)
+	if err != nil {
+		t.Fatalf("RecoverTypedDataSigner() failed: %v", err)
+	}
+	if recovered != signer.GetAddress() {
+		t.Errorf("recovered = %s, expected %s", recovered.Hex(), signer.GetAddress().Hex())
+	}
+
+	if !VerifyTypedDataSignature(signer.GetAddress(), typedData, signature) {
+		t.Error("Valid typed data signature verification failed")
+	}
+
+	// 修改消息后验证失败
+	typedData.Message["contents"] = "Hello, Alice!"
+	if VerifyTypedDataSignature(signer.GetAddress(), typedData, signature) {
+		t.Error("Tampered typed data signature verification should fail")
+	}
+
+	if _, err := RecoverTypedDataSigner(typedData, signature[:10]); err == nil {
+		t.Error("Expected error for short signature")
+	}
+}
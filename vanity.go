@@ -0,0 +1,251 @@
+package etherkit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+//############ Vanity Address ############
+
+// DefaultVanityProgressInterval 默认的进度回调间隔
+const DefaultVanityProgressInterval = time.Second
+
+// VanityOptions 靓号地址的搜索条件，Prefix/Suffix/Pattern 至少设置一个，同时设置时需全部满足
+type VanityOptions struct {
+	Prefix  string         // 地址前缀（十六进制，可带0x）
+	Suffix  string         // 地址后缀（十六进制）
+	Pattern *regexp.Regexp // 匹配不带0x的40位十六进制地址
+
+	// CaseSensitive 为 true 时按 EIP-55 校验和格式匹配大小写，否则按小写匹配
+	CaseSensitive bool
+
+	Workers          int                  // 并行数，默认 runtime.NumCPU()
+	Progress         func(VanityProgress) // 进度回调，在单独的 goroutine 中调用
+	ProgressInterval time.Duration        // 进度回调间隔，默认 DefaultVanityProgressInterval
+}
+
+// VanityProgress 搜索进度
+type VanityProgress struct {
+	Attempts uint64        // 已尝试次数
+	Elapsed  time.Duration // 已用时间
+	Rate     float64       // 每秒尝试次数
+	Expected float64       // 期望尝试次数（仅根据前缀和后缀估算），0表示无法估算
+}
+
+// VanityAddressResult 找到的靓号账户
+type VanityAddressResult struct {
+	Signer   *Signer
+	Attempts uint64
+}
+
+// Create2SaltResult 找到的 CREATE2 salt
+type Create2SaltResult struct {
+	Salt     [32]byte
+	Address  common.Address
+	Attempts uint64
+}
+
+// GenerateVanityAddress 并行生成私钥，直到地址满足搜索条件。ctx 取消时返回 ctx.Err()
+func GenerateVanityAddress(ctx context.Context, opts VanityOptions) (*VanityAddressResult, error) {
+	match, err := opts.matcher()
+	if err != nil {
+		return nil, err
+	}
+
+	signer, attempts, err := searchVanity(ctx, opts, func(int) func() (*Signer, bool, error) {
+		return func() (*Signer, bool, error) {
+			key, err := GeneratePrivateKey()
+			if err != nil {
+				return nil, false, errors.Wrap(err, "generate private key error")
+			}
+			if !match(PrivateKeyToAddress(key)) {
+				ZeroPrivateKey(key)
+				return nil, false, nil
+			}
+			signer, err := NewSignerFromPrivateKey(key)
+			return signer, true, err
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &VanityAddressResult{Signer: signer, Attempts: attempts}, nil
+}
+
+// MineCreate2Salt 并行搜索 salt，使 deployer 用 CREATE2 部署 initCodeHash 对应的合约时地址满足搜索条件
+func MineCreate2Salt(ctx context.Context, deployer common.Address, initCodeHash common.Hash, opts VanityOptions) (*Create2SaltResult, error) {
+	match, err := opts.matcher()
+	if err != nil {
+		return nil, err
+	}
+
+	result, attempts, err := searchVanity(ctx, opts, func(int) func() (*Create2SaltResult, bool, error) {
+		// 每个 worker 从随机 salt 开始，递增最后8个字节
+		var salt [32]byte
+		_, randErr := rand.Read(salt[:])
+		return func() (*Create2SaltResult, bool, error) {
+			if randErr != nil {
+				return nil, false, errors.Wrap(randErr, "failed to generate salt")
+			}
+			binary.BigEndian.PutUint64(salt[24:], binary.BigEndian.Uint64(salt[24:])+1)
+			address := ComputeCreate2AddressFromHash(deployer, salt, initCodeHash)
+			if !match(address) {
+				return nil, false, nil
+			}
+			return &Create2SaltResult{Salt: salt, Address: address}, true, nil
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Attempts = attempts
+	return result, nil
+}
+
+// EstimateAttempts 估算满足前缀和后缀所需的期望尝试次数
+func (opts VanityOptions) EstimateAttempts() float64 {
+	pattern := strings.TrimPrefix(strings.TrimPrefix(opts.Prefix, "0x"), "0X") + opts.Suffix
+	if pattern == "" {
+		return 0
+	}
+	expected := math.Pow(16, float64(len(pattern)))
+	if opts.CaseSensitive {
+		// 字母的大小写由校验和决定，各有1/2的概率
+		for _, c := range pattern {
+			if (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') {
+				expected *= 2
+			}
+		}
+	}
+	return expected
+}
+
+// matcher 校验搜索条件并返回地址匹配函数
+func (opts VanityOptions) matcher() (func(common.Address) bool, error) {
+	prefix := strings.TrimPrefix(strings.TrimPrefix(opts.Prefix, "0x"), "0X")
+	suffix := opts.Suffix
+	if prefix == "" && suffix == "" && opts.Pattern == nil {
+		return nil, errors.Wrap(ErrInvalidVanityPattern, "prefix, suffix or pattern is required")
+	}
+	if len(prefix)+len(suffix) > common.AddressLength*2 {
+		return nil, errors.Wrap(ErrInvalidVanityPattern, "prefix and suffix are too long")
+	}
+	for _, part := range []string{prefix, suffix} {
+		if _, err := hex.DecodeString(strings.Repeat("0", len(part)%2) + part); err != nil {
+			return nil, errors.Wrapf(ErrInvalidVanityPattern, "%q is not hex", part)
+		}
+	}
+	if !opts.CaseSensitive {
+		prefix, suffix = strings.ToLower(prefix), strings.ToLower(suffix)
+	}
+
+	return func(address common.Address) bool {
+		var s string
+		if opts.CaseSensitive {
+			s = address.Hex()[2:]
+		} else {
+			s = hex.EncodeToString(address.Bytes())
+		}
+		return strings.HasPrefix(s, prefix) && strings.HasSuffix(s, suffix) &&
+			(opts.Pattern == nil || opts.Pattern.MatchString(s))
+	}, nil
+}
+
+// searchVanity 启动多个 worker 反复调用 newWorker 返回的尝试函数，直到找到结果、出错或 ctx 取消
+func searchVanity[T any](ctx context.Context, opts VanityOptions, newWorker func(worker int) func() (T, bool, error)) (T, uint64, error) {
+	var zero T
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		attempts uint64
+		once     sync.Once
+		result   T
+		err      error
+		wg       sync.WaitGroup
+	)
+	finish := func(r T, e error) {
+		once.Do(func() {
+			result, err = r, e
+			cancel()
+		})
+	}
+
+	start := time.Now()
+	// progressDone 在进度 goroutine 退出后关闭，返回前等待它，保证返回后不再回调 Progress
+	progressDone := make(chan struct{})
+	if opts.Progress == nil {
+		close(progressDone)
+	} else {
+		interval := opts.ProgressInterval
+		if interval <= 0 {
+			interval = DefaultVanityProgressInterval
+		}
+		expected := opts.EstimateAttempts()
+		go func() {
+			defer close(progressDone)
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					n := atomic.LoadUint64(&attempts)
+					elapsed := time.Since(start)
+					opts.Progress(VanityProgress{
+						Attempts: n,
+						Elapsed:  elapsed,
+						Rate:     float64(n) / elapsed.Seconds(),
+						Expected: expected,
+					})
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(try func() (T, bool, error)) {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				r, ok, e := try()
+				atomic.AddUint64(&attempts, 1)
+				if e != nil || ok {
+					finish(r, e)
+					return
+				}
+			}
+		}(newWorker(i))
+	}
+	wg.Wait()
+	cancel()
+	<-progressDone
+
+	total := atomic.LoadUint64(&attempts)
+	once.Do(func() {
+		// 没有 worker 找到结果，说明外部 ctx 被取消
+		err = ctx.Err()
+	})
+	if err != nil {
+		return zero, total, err
+	}
+	return result, total, nil
+}
@@ -0,0 +1,103 @@
+package etherkit
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestGenerateVanityAddress(t *testing.T) {
+	result, err := GenerateVanityAddress(context.Background(), VanityOptions{Prefix: "0xa", Suffix: "B", Workers: 2})
+	if err != nil {
+		t.Fatalf("GenerateVanityAddress() failed: %v", err)
+	}
+	address := strings.ToLower(result.Signer.GetAddress().Hex())
+	if !strings.HasPrefix(address, "0xa") || !strings.HasSuffix(address, "b") {
+		t.Errorf("Address %s does not match", address)
+	}
+	if PrivateKeyToAddress(result.Signer.GetPrivateKey()) != result.Signer.GetAddress() || result.Attempts == 0 {
+		t.Errorf("Unexpected result: %s, %d attempts", result.Signer, result.Attempts)
+	}
+
+	// 按校验和大小写匹配
+	result, err = GenerateVanityAddress(context.Background(), VanityOptions{Prefix: "E", CaseSensitive: true})
+	if err != nil {
+		t.Fatalf("GenerateVanityAddress() failed: %v", err)
+	}
+	if !strings.HasPrefix(result.Signer.GetAddress().Hex(), "0xE") {
+		t.Errorf("Address %s does not match", result.Signer.GetAddress().Hex())
+	}
+
+	result, err = GenerateVanityAddress(context.Background(), VanityOptions{Pattern: regexp.MustCompile(`^[0-9]{2}`)})
+	if err != nil {
+		t.Fatalf("GenerateVanityAddress() failed: %v", err)
+	}
+	if !regexp.MustCompile(`^0x[0-9]{2}`).MatchString(result.Signer.GetAddress().Hex()) {
+		t.Errorf("Address %s does not match", result.Signer.GetAddress().Hex())
+	}
+}
+
+func TestMineCreate2Salt(t *testing.T) {
+	deployer := common.HexToAddress("0x4e59b44847b379578588920cA78FbF26c0B4956C")
+	initCodeHash := crypto.Keccak256Hash([]byte{0x60, 0x00})
+
+	result, err := MineCreate2Salt(context.Background(), deployer, initCodeHash, VanityOptions{Prefix: "00"})
+	if err != nil {
+		t.Fatalf("MineCreate2Salt() failed: %v", err)
+	}
+	if result.Address != crypto.CreateAddress2(deployer, result.Salt, initCodeHash.Bytes()) {
+		t.Errorf("Address %s does not match salt", result.Address.Hex())
+	}
+	if result.Address[0] != 0 {
+		t.Errorf("Address %s does not start with 00", result.Address.Hex())
+	}
+}
+
+func TestVanityCancellation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var reports int32
+	opts := VanityOptions{
+		Prefix:           "0000000000000000",
+		Progress:         func(VanityProgress) { atomic.AddInt32(&reports, 1) },
+		ProgressInterval: 10 * time.Millisecond,
+	}
+	_, err := MineCreate2Salt(ctx, common.Address{}, common.Hash{}, opts)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	reported := atomic.LoadInt32(&reports)
+	if reported == 0 {
+		t.Error("Expected progress reports")
+	}
+	// 返回后不再回调 Progress
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt32(&reports); n != reported {
+		t.Errorf("Progress called %d times after return", n-reported)
+	}
+}
+
+func TestVanityOptionsInvalid(t *testing.T) {
+	for _, opts := range []VanityOptions{
+		{},
+		{Prefix: "0xg"},
+		{Suffix: "xyz"},
+		{Prefix: strings.Repeat("a", 41)},
+	} {
+		if _, err := GenerateVanityAddress(context.Background(), opts); !errors.Is(err, ErrInvalidVanityPattern) {
+			t.Errorf("GenerateVanityAddress(%+v) expected ErrInvalidVanityPattern, got %v", opts, err)
+		}
+	}
+
+	if expected := (VanityOptions{Prefix: "0xdead", CaseSensitive: true}).EstimateAttempts(); expected != 65536*16 {
+		t.Errorf("EstimateAttempts() = %f, expected %d", expected, 65536*16)
+	}
+}
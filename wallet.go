@@ -2,7 +2,11 @@ package etherkit
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"fmt"
 	"math/big"
+	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -25,18 +29,29 @@ type EtherWallet interface {
 	GetBalance() (*big.Int, error)
 	NewTx(to common.Address, nonce, gasLimit uint64, gasPrice, value *big.Int, data []byte) (*types.Transaction, error)
 	SendTx(to common.Address, nonce, gasLimit uint64, gasPrice, value *big.Int, data []byte) (common.Hash, error)
+	SendTxTo(recipient string, nonce, gasLimit uint64, gasPrice, value *big.Int, data []byte) (common.Hash, error)
 	NewTxWithHexInput(to common.Address, nonce, gasLimit uint64, gasPrice, value *big.Int, input string) (*types.Transaction, error)
 	SendTxWithHexInput(to common.Address, nonce, gasLimit uint64, gasPrice, value *big.Int, input string) (common.Hash, error)
 	BuildTxOpts(value, nonce, gasPrice *big.Int) (*bind.TransactOpts, error)
 	SignTx(tx *types.Transaction) (*types.Transaction, error)
 	SendSignedTx(signedTx *types.Transaction) (common.Hash, error)
 	Signature(data []byte) ([]byte, error)
+	SignTypedData(typedData *TypedData) ([]byte, error)
+	SignPersonalMessage(message []byte) ([]byte, error)
+	DeployContract(ctx context.Context, contractAbi abi.ABI, bytecode string, opts *DeployOptions, args ...interface{}) (*DeployResult, error)
 	CallContract(contractAddress common.Address, contractAbi abi.ABI, functionName string, params ...interface{}) ([]interface{}, error)
+	CallContractWithOptions(ctx context.Context, contractAddress common.Address, contractAbi abi.ABI, functionName string, opts *CallOptions, params ...interface{}) ([]interface{}, error)
+	EthCall(ctx context.Context, to common.Address, data []byte, opts *CallOptions) ([]byte, error)
 }
 
+// Wallet 钱包。String/Format/MarshalJSON 只输出地址，不会输出私钥
 type Wallet struct {
 	es EtherSigner
 	ep EtherProvider
+
+	mu            sync.RWMutex
+	preSendChecks []PreSendCheck
+	addressBook   *AddressBook
 }
 
 // NewWallet 新建一个Wallet
@@ -96,6 +111,15 @@ func (w *Wallet) GetBalance() (*big.Int, error) {
 	return w.GetClient().BalanceAt(context.Background(), w.GetAddress(), nil)
 }
 
+// PredictNextContractAddress 预测钱包下一笔交易（使用 pending nonce）以 CREATE 部署的合约地址
+func (w *Wallet) PredictNextContractAddress() (common.Address, error) {
+	nonce, err := w.GetNonce()
+	if err != nil {
+		return common.Address{}, err
+	}
+	return ComputeContractAddress(w.GetAddress(), nonce), nil
+}
+
 // NewTx 构建一笔交易。nonce传0表示字段计算；gasLimit传0表示字段计算；gasPrice穿nil或者big.NewInt(0)表示gasPrice自动计算。
 func (w *Wallet) NewTx(to common.Address, nonce, gasLimit uint64, gasPrice, value *big.Int, data []byte) (*types.Transaction, error) {
 
@@ -142,6 +166,37 @@ func (w *Wallet) SendTx(to common.Address, nonce, gasLimit uint64, gasPrice, val
 	return w.SendSignedTx(signedTx)
 }
 
+// SetAddressBook 设置钱包使用的地址簿，用于 SendTxTo 和 ResolveAddress 解析标签
+func (w *Wallet) SetAddressBook(book *AddressBook) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.addressBook = book
+}
+
+// GetAddressBook 获得钱包使用的地址簿，未设置时返回 nil
+func (w *Wallet) GetAddressBook() *AddressBook {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.addressBook
+}
+
+// ResolveAddress 将地址簿标签或十六进制地址解析为地址，大小写混合的地址必须符合 EIP-55 校验和
+func (w *Wallet) ResolveAddress(labelOrAddress string) (common.Address, error) {
+	if book := w.GetAddressBook(); book != nil {
+		return book.Resolve(labelOrAddress)
+	}
+	return parseAddress(strings.TrimSpace(labelOrAddress), nil)
+}
+
+// SendTxTo 与 SendTx 相同，但接收者可以是地址簿中的标签或十六进制地址
+func (w *Wallet) SendTxTo(recipient string, nonce, gasLimit uint64, gasPrice, value *big.Int, data []byte) (common.Hash, error) {
+	to, err := w.ResolveAddress(recipient)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return w.SendTx(to, nonce, gasLimit, gasPrice, value, data)
+}
+
 // NewTxWithHexInput 构建一笔交易，使用0x开头的input。nonce传0表示字段计算；gasLimit传0表示字段计算；gasPrice穿nil或者big.NewInt(0)表示gasPrice自动计算。
 func (w *Wallet) NewTxWithHexInput(to common.Address, nonce, gasLimit uint64, gasPrice, value *big.Int, input string) (*types.Transaction, error) {
 	data, err := hexutil.Decode(input)
@@ -168,7 +223,7 @@ func (w *Wallet) BuildTxOpts(value, nonce, gasPrice *big.Int) (*bind.TransactOpt
 		return nil, err
 	}
 
-	txOpts, _ := bind.NewKeyedTransactorWithChainID(w.GetEthSigner().GetPrivateKey(), chainId)
+	txOpts := w.newTransactOpts(chainId)
 
 	txOpts.Value = value
 
@@ -206,7 +261,11 @@ func (w *Wallet) SignTx(tx *types.Transaction) (*types.Transaction, error) {
 
 	// 使用伦敦签名
 	signer := types.NewLondonSigner(chainId)
-	signedTx, err := types.SignTx(tx, signer, w.es.GetPrivateKey())
+	var signedTx *types.Transaction
+	err = withSignerPrivateKey(w.es, func(pk *ecdsa.PrivateKey) error {
+		signedTx, err = types.SignTx(tx, signer, pk)
+		return err
+	})
 	if err != nil {
 		return &types.Transaction{}, err
 	}
@@ -214,8 +273,31 @@ func (w *Wallet) SignTx(tx *types.Transaction) (*types.Transaction, error) {
 	return signedTx, nil
 }
 
-// SendSignedTx 发送签名后的Tx
+// AddPreSendCheck 注册发送交易前的检查（如 AddressGuard.PreSendCheck），任一检查失败时交易不会被发送
+func (w *Wallet) AddPreSendCheck(check PreSendCheck) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.preSendChecks = append(w.preSendChecks, check)
+}
+
+// runPreSendChecks 依次执行已注册的发送前检查
+func (w *Wallet) runPreSendChecks(tx *types.Transaction) error {
+	w.mu.RLock()
+	checks := w.preSendChecks
+	w.mu.RUnlock()
+	for _, check := range checks {
+		if err := check(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendSignedTx 发送签名后的Tx，发送前会执行 AddPreSendCheck 注册的检查
 func (w *Wallet) SendSignedTx(signedTx *types.Transaction) (common.Hash, error) {
+	if err := w.runPreSendChecks(signedTx); err != nil {
+		return [32]byte{}, err
+	}
 	err := w.GetClient().SendTransaction(context.Background(), signedTx)
 	if err != nil {
 		return [32]byte{}, err
@@ -224,11 +306,68 @@ func (w *Wallet) SendSignedTx(signedTx *types.Transaction) (common.Hash, error)
 }
 
 // Signature 生成一个签名
-func (w *Wallet) Signature(data []byte) ([]byte, error) {
-	key := w.es.GetPrivateKey()
+func (w *Wallet) Signature(data []byte) (signature []byte, err error) {
 	hash := crypto.Keccak256Hash(data)
 
-	return crypto.Sign(hash.Bytes(), key)
+	err = withSignerPrivateKey(w.es, func(pk *ecdsa.PrivateKey) error {
+		signature, err = crypto.Sign(hash.Bytes(), pk)
+		return err
+	})
+	return signature, err
+}
+
+// SignTypedData 对EIP-712结构化数据签名
+func (w *Wallet) SignTypedData(typedData *TypedData) (signature []byte, err error) {
+	err = withSignerPrivateKey(w.es, func(pk *ecdsa.PrivateKey) error {
+		signature, err = SignTypedData(pk, typedData)
+		return err
+	})
+	return signature, err
+}
+
+// SignPersonalMessage 对 EIP-191 personal_sign 消息签名
+func (w *Wallet) SignPersonalMessage(message []byte) (signature []byte, err error) {
+	err = withSignerPrivateKey(w.es, func(pk *ecdsa.PrivateKey) error {
+		signature, err = SignPersonalMessage(pk, message)
+		return err
+	})
+	return signature, err
+}
+
+// String 只输出地址
+func (w *Wallet) String() string {
+	return redactedSignerString("Wallet", w.GetAddress())
+}
+
+// Format 实现 fmt.Formatter，保证任何格式都不会输出私钥
+func (w *Wallet) Format(f fmt.State, verb rune) {
+	_, _ = fmt.Fprint(f, w.String())
+}
+
+// MarshalJSON 只序列化地址
+func (w *Wallet) MarshalJSON() ([]byte, error) {
+	return marshalRedactedSigner(w.GetAddress())
+}
+
+// newTransactOpts 创建与 bind.NewKeyedTransactorWithChainID 等价的 TransactOpts，
+// 但只在签名时才取用私钥，不会在闭包中长期持有
+func (w *Wallet) newTransactOpts(chainId *big.Int) *bind.TransactOpts {
+	from := w.GetAddress()
+	signer := types.LatestSignerForChainID(chainId)
+	return &bind.TransactOpts{
+		From: from,
+		Signer: func(address common.Address, tx *types.Transaction) (signedTx *types.Transaction, err error) {
+			if address != from {
+				return nil, bind.ErrNotAuthorized
+			}
+			err = withSignerPrivateKey(w.es, func(pk *ecdsa.PrivateKey) error {
+				signedTx, err = types.SignTx(tx, signer, pk)
+				return err
+			})
+			return signedTx, err
+		},
+		Context: context.Background(),
+	}
 }
 
 // CallContract 调用合约的方法，无需创建交易